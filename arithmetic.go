@@ -0,0 +1,98 @@
+package key
+
+import "math/bits"
+
+// Add returns the key which is n keys after the key in the order
+// of IDs. It returns an error that wraps the ErrOverflow if the result
+// is out of the key space, i.e. isn't less than Total.
+//
+// Example usage:
+//
+//	ls, _ := New("abc", 3)
+//	key, err := ls.Add("bab", 5)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(key) // Output: "bca"
+func (ls *Locksmith) Add(key string, n uint64) (string, error) {
+	id, err := ls.Unmarshal(key)
+	if err != nil {
+		return "", err
+	}
+
+	sum, carry := bits.Add64(id, n, 0)
+	if carry != 0 || !ls.fits(sum) {
+		return "", errorf(ErrOverflow,
+			"the %q key plus %d is out of the key space", key, n)
+	}
+
+	return ls.Marshal(sum)
+}
+
+// Sub returns the key which is n keys before the key in the order
+// of IDs. It returns an error that wraps the ErrOverflow if the result
+// is less than zero ID.
+func (ls *Locksmith) Sub(key string, n uint64) (string, error) {
+	id, err := ls.Unmarshal(key)
+	if err != nil {
+		return "", err
+	}
+
+	if n > id {
+		return "", errorf(ErrOverflow, "the %q key minus %d is less "+
+			"than the first key", key, n)
+	}
+
+	return ls.Marshal(id - n)
+}
+
+// Compare compares two keys in the order of IDs. The result is 0
+// if a == b, -1 if a < b, and +1 if a > b.
+//
+// The keys of the dynamic size or of the unsorted alphabet can't be
+// compared as strings, so the method compares their IDs.
+func (ls *Locksmith) Compare(a, b string) (int, error) {
+	x, y, err := ls.pair(a, b)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case x < y:
+		return -1, nil
+	case x > y:
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+// Distance returns the number of steps between two keys in the order
+// of IDs, i.e. the absolute difference of their IDs.
+func (ls *Locksmith) Distance(a, b string) (uint64, error) {
+	x, y, err := ls.pair(a, b)
+	if err != nil {
+		return 0, err
+	}
+
+	if x < y {
+		return y - x, nil
+	}
+
+	return x - y, nil
+}
+
+// The pair decodes two keys into their IDs.
+func (ls *Locksmith) pair(a, b string) (uint64, uint64, error) {
+	x, err := ls.Unmarshal(a)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	y, err := ls.Unmarshal(b)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return x, y, nil
+}
@@ -0,0 +1,92 @@
+package key
+
+import (
+	"math"
+	"testing"
+)
+
+// TestAdd tests the Add and Sub methods.
+func TestAdd(t *testing.T) {
+	fixed, _ := New("abc", 3)
+	dynamic, _ := New("abc")
+	last, _ := dynamic.Marshal(math.MaxUint64)
+
+	tests := []struct {
+		name string
+		ls   *Locksmith
+		key  string
+		add  uint64
+		sub  uint64
+		want string
+		err  bool
+	}{
+		{"Add", fixed, "bab", 5, 0, "bca", false},
+		{"Sub", fixed, "bca", 0, 5, "bab", false},
+		{"Add to last", fixed, "bab", 16, 0, "ccc", false},
+		{"Add out of range", fixed, "bab", 17, 0, "", true},
+		{"Sub out of range", fixed, "bab", 0, 11, "", true},
+		{"Dynamic grows", dynamic, "cc", 1, 0, "baa", false},
+		{"Dynamic shrinks", dynamic, "baa", 0, 1, "cc", false},
+		{"Overflow", dynamic, last, 1, 0, "", true},
+		{"Invalid key", fixed, "abd", 1, 0, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got string
+			var err error
+			if test.sub != 0 {
+				got, err = test.ls.Sub(test.key, test.sub)
+			} else {
+				got, err = test.ls.Add(test.key, test.add)
+			}
+
+			if (err != nil) != test.err {
+				t.Fatalf("error = %v, want error %v", err, test.err)
+			}
+
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+// TestCompare tests the Compare and Distance methods.
+func TestCompare(t *testing.T) {
+	// The keys of the unsorted alphabet don't sort as strings.
+	ls, _ := New("cba")
+
+	tests := []struct {
+		a, b     string
+		cmp      int
+		distance uint64
+	}{
+		{"b", "b", 0, 0},
+		{"c", "a", -1, 2},
+		{"a", "bc", -1, 1},
+		{"bcc", "ba", 1, 4},
+	}
+
+	for _, test := range tests {
+		cmp, err := ls.Compare(test.a, test.b)
+		if err != nil || cmp != test.cmp {
+			t.Errorf("Compare(%q, %q) = %d, %v, want %d",
+				test.a, test.b, cmp, err, test.cmp)
+		}
+
+		d, err := ls.Distance(test.a, test.b)
+		if err != nil || d != test.distance {
+			t.Errorf("Distance(%q, %q) = %d, %v, want %d",
+				test.a, test.b, d, err, test.distance)
+		}
+	}
+
+	if _, err := ls.Compare("a", "d"); err == nil {
+		t.Error("Compare() with invalid key should return an error")
+	}
+
+	if _, err := ls.Distance("d", "a"); err == nil {
+		t.Error("Distance() with invalid key should return an error")
+	}
+}
@@ -0,0 +1,234 @@
+package key
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+// The golden is the fractional part of the golden ratio as 64-bit
+// fixed-point number, it's used to calculate the shift of the ID
+// far enough from the blocked one.
+const golden = 0x9E3779B97F4A7C15
+
+// The leet maps the characters of the leetspeak to letters.
+// The '1' and '|' characters are checked as 'i' and 'l' both.
+var leet = map[rune]rune{
+	'0': 'o',
+	'1': 'i',
+	'2': 'z',
+	'3': 'e',
+	'4': 'a',
+	'5': 's',
+	'6': 'g',
+	'7': 't',
+	'8': 'b',
+	'9': 'g',
+	'@': 'a',
+	'$': 's',
+	'!': 'i',
+	'|': 'i',
+	'+': 't',
+}
+
+// The blocklist is a list of banned words.
+type blocklist struct {
+	words []string // normalized banned words
+}
+
+// WithBlocklist sets the list of words that mustn't appear in the keys.
+//
+// The words are matched as substrings of the key ignoring case and the
+// group separators, and taking into account the leetspeak, for example
+// the "b4d" and "8AD" keys are both matched by the "bad" word.
+//
+// To keep the conversion reversible, the key gets one extra character
+// at the beginning - the tweak. If the key of the ID contains a banned
+// word, the ID is shifted by the tweak number and encoded again until
+// the key is clean. The Unmarshal method shifts the ID back. The number
+// of attempts is limited by the size of the alphabet of the first
+// position, if all of them are blocked the Marshal returns an error.
+//
+// The Unmarshal method accepts only the keys that can be generated by
+// the Marshal method, so each ID still has exactly one key.
+func WithBlocklist(words ...string) Option {
+	return func(ls *Locksmith) error {
+		if len(words) == 0 {
+			return errors.New("blank blocklist")
+		}
+
+		list := &blocklist{words: make([]string, 0, len(words))}
+		for _, word := range words {
+			normalized := normalizeLeet(word, 'i')
+			if normalized == "" {
+				return errors.New("the blocklist contains a blank word")
+			}
+
+			list.words = append(list.words, normalized)
+		}
+
+		ls.blocklist = list
+		return nil
+	}
+}
+
+// The match returns true if the text contains any banned word.
+func (bl *blocklist) match(text string) bool {
+	variants := [...]string{
+		strings.ToLower(text),
+		normalizeLeet(text, 'i'),
+		normalizeLeet(text, 'l'),
+	}
+
+	for _, word := range bl.words {
+		for _, v := range variants {
+			if strings.Contains(v, word) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// The normalizeLeet converts the text to lower case and replaces the
+// leetspeak characters with letters, the one is the letter used for
+// the '1' and '|' characters.
+func normalizeLeet(text string, one rune) string {
+	return strings.Map(func(r rune) rune {
+		if l, ok := leet[r]; ok {
+			if l == 'i' && (r == '1' || r == '|') {
+				return one
+			}
+
+			return l
+		}
+
+		return unicode.ToLower(r)
+	}, text)
+}
+
+// The censor converts the ID into the key that doesn't contain
+// banned words.
+func (ls *Locksmith) censor(id uint64) (string, error) {
+	size, total := ls.ring(len(digits(id, ls.base(0), 0)))
+	tweaks, _ := ls.digit(0)
+	for tweak := range tweaks {
+		value := []int{tweak}
+		shifted := shift(id, tweak, total)
+		if ls.pattern != nil {
+			value = append(value, ls.split(shifted)...)
+		} else {
+			value = append(value, digits(shifted, ls.base(0), size)...)
+		}
+
+		key := ls.format(value)
+		if !ls.blocklist.match(ls.strip(key)) {
+			return key, nil
+		}
+	}
+
+	return "", errorf(ErrIDOutOfRange,
+		"all keys of the %d ID are blocked", id)
+}
+
+// The uncensor converts the digits of the key generated by the censor
+// method back into the ID.
+func (ls *Locksmith) uncensor(key string, value []int) (uint64, error) {
+	shifted, ok := ls.join(value[1:])
+	if !ok {
+		return 0, errorf(ErrOverflow, "the %q key is out of range", key)
+	}
+
+	// The key must be the same one that the Marshal generates,
+	// otherwise the ID has several keys.
+	_, total := ls.ring(len(value) - 1)
+	id := unshift(shifted, value[0], total)
+	canonical, err := ls.censor(id)
+	if err != nil {
+		return 0, err
+	}
+
+	if canonical != key {
+		return 0, errorf(ErrInvalidChar,
+			"the %q key is blocked or isn't canonical", key)
+	}
+
+	return id, nil
+}
+
+// The ring returns the size of the key without the tweak and the number
+// of keys of this size, the IDs are shifted within this number.
+//
+// For the fixed size keys it's the Total value. The dynamic size keys
+// are shifted among the keys of the same length, so the length of the
+// key is defined by the length of the ID, the length argument.
+func (ls *Locksmith) ring(length int) (int, uint64) {
+	if ls.size != 0 {
+		return int(ls.size), ls.total
+	}
+
+	if total, ok := capacity(ls.base(0), length); ok {
+		return length, total
+	}
+
+	return length, math.MaxUint64
+}
+
+// The strip removes the prefix and the group separators from the key.
+func (ls *Locksmith) strip(key string) string {
+	key = strings.TrimPrefix(key, ls.prefix)
+	if ls.group == 0 {
+		return key
+	}
+
+	return strings.ReplaceAll(key, string(ls.separator), "")
+}
+
+// The stride returns the distance between the IDs of the neighboring
+// tweaks, it's about 0.618 of the total number of keys. The total as
+// MaxUint64 means the whole uint64 range.
+func stride(total uint64) uint64 {
+	if total == math.MaxUint64 {
+		return golden
+	}
+
+	hi, _ := bits.Mul64(total, golden)
+	return hi
+}
+
+// The shift moves the ID forward by the tweak number of strides
+// in the ring of the total number of keys.
+func shift(id uint64, tweak int, total uint64) uint64 {
+	step := stride(total)
+	for i := 0; i < tweak; i++ {
+		if total == math.MaxUint64 {
+			id += step // wraps around 2^64
+		} else if id >= total-step {
+			id -= total - step
+		} else {
+			id += step
+		}
+	}
+
+	return id
+}
+
+// The unshift moves the ID back by the tweak number of strides,
+// it's the inverse of the shift function.
+func unshift(id uint64, tweak int, total uint64) uint64 {
+	step := stride(total)
+	for i := 0; i < tweak; i++ {
+		if total == math.MaxUint64 {
+			id -= step // wraps around 2^64
+		} else if id < step {
+			id += total - step
+		} else {
+			id -= step
+		}
+	}
+
+	return id
+}
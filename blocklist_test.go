@@ -0,0 +1,162 @@
+package key
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestBlocklistMatch tests the matching of the banned words.
+func TestBlocklistMatch(t *testing.T) {
+	ls, _ := New("abcdefghijklmnopqrstuvwxyz0123456789")
+	ls, err := ls.With(WithBlocklist("bad", "Lol"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		text   string
+		expect bool
+	}{
+		{"bad", true},
+		{"xxBADxx", true},
+		{"b4d", true},
+		{"8ad", true},
+		{"101", true},
+		{"l0l", true},
+		{"bat", false},
+		{"lo", false},
+	}
+
+	for _, test := range tests {
+		if r := ls.blocklist.match(test.text); r != test.expect {
+			t.Errorf("%s: expected %v but %v", test.text, test.expect, r)
+		}
+	}
+
+	if _, err := ls.With(WithBlocklist()); err == nil {
+		t.Error("expected an error for the blank blocklist")
+	}
+
+	if _, err := ls.With(WithBlocklist("bad", "")); err == nil {
+		t.Error("expected an error for the blank word")
+	}
+}
+
+// TestBlocklistMarshal tests that the Marshal method never generates
+// the blocked keys and the Unmarshal method recovers the ID.
+func TestBlocklistMarshal(t *testing.T) {
+	for _, size := range []int{0, 3} {
+		ls, _ := New("abcd", size)
+		ls, err := ls.With(WithBlocklist("bad", "dab", "cab"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		seen := make(map[string]bool)
+		for id := uint64(0); id < 64; id++ {
+			key, err := ls.Marshal(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, word := range []string{"bad", "dab", "cab"} {
+				if strings.Contains(key, word) {
+					t.Errorf("the %q key contains %q", key, word)
+				}
+			}
+
+			if seen[key] {
+				t.Errorf("the %q key is duplicated", key)
+			}
+			seen[key] = true
+
+			result, err := ls.Unmarshal(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result != id {
+				t.Errorf("%s: expected %d but %d", key, id, result)
+			}
+		}
+	}
+}
+
+// TestBlocklistUnmarshal tests that the blocked and non-canonical
+// keys aren't accepted.
+func TestBlocklistUnmarshal(t *testing.T) {
+	ls, _ := New("abcd", 3)
+	ls, err := ls.With(WithBlocklist("bad"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first character is the tweak.
+	key, _ := ls.Marshal(1)
+	if key != "aaab" {
+		t.Errorf("expected %q but %q", "aaab", key)
+	}
+
+	tests := []string{
+		"abad", // blocked
+		"baab", // not canonical, the "aaab" is clean
+		"aab",  // wrong size
+		"a",    // too short
+	}
+
+	for _, test := range tests {
+		if _, err := ls.Unmarshal(test); err == nil {
+			t.Errorf("%s: expected an error", test)
+		}
+	}
+
+	// The ID of the blocked key gets another key.
+	id, _ := New("abcd", 3)
+	bad, _ := id.Unmarshal("bad")
+	key, err = ls.Marshal(bad)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key[0] == 'a' || strings.Contains(key, "bad") {
+		t.Errorf("unexpected key %q", key)
+	}
+
+	if result, _ := ls.Unmarshal(key); result != bad {
+		t.Errorf("expected %d but %d", bad, result)
+	}
+}
+
+// TestBlocklistGroups tests the blocklist together with the groups.
+func TestBlocklistGroups(t *testing.T) {
+	ls, _ := New("abcd")
+	ls, err := ls.With(WithGroups(2, '-'), WithBlocklist("bad"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []uint64{0, 100, math.MaxUint64} {
+		key, err := ls.Marshal(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Contains(strings.ReplaceAll(key, "-", ""), "bad") {
+			t.Errorf("the %q key is blocked", key)
+		}
+
+		if result, _ := ls.Unmarshal(key); result != id {
+			t.Errorf("%s: expected %d but %d", key, id, result)
+		}
+	}
+}
+
+// TestBlocklistExhausted tests the error when all keys are blocked.
+func TestBlocklistExhausted(t *testing.T) {
+	ls, _ := New("ab", 2)
+	ls, _ = ls.With(WithBlocklist("aa", "ab", "ba", "bb"))
+	if _, err := ls.Marshal(1); err == nil {
+		t.Error("expected an error when all keys are blocked")
+	}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/goloop/key"
+)
+
+// The encode executes the encode command.
+func encode(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var cfg config
+	fs := newFlagSet("encode", "plain", &cfg, stderr)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	ls, err := cfg.locksmith()
+	if err != nil {
+		fmt.Fprintf(stderr, "key: %v\n", err)
+		return exitUsage
+	}
+
+	w := newWriter(cfg.format, "key", stdout, stderr)
+	err = values(fs.Args(), stdin, func(value string) {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			w.write(record{Error: fmt.Sprintf("invalid ID %q", value)})
+			return
+		}
+
+		r := record{ID: &id}
+		if r.Key, err = ls.Marshal(id); err != nil {
+			r.Error = err.Error()
+		}
+
+		w.write(r)
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "key: %v\n", err)
+		w.close()
+		return exitFail
+	}
+
+	return w.close()
+}
+
+// The decode executes the decode command.
+func decode(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var cfg config
+	fs := newFlagSet("decode", "plain", &cfg, stderr)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	ls, err := cfg.locksmith()
+	if err != nil {
+		fmt.Fprintf(stderr, "key: %v\n", err)
+		return exitUsage
+	}
+
+	w := newWriter(cfg.format, "id", stdout, stderr)
+	err = values(fs.Args(), stdin, func(value string) {
+		r := record{Key: value}
+		if id, err := ls.Unmarshal(value); err != nil {
+			r.Error = err.Error()
+		} else {
+			r.ID = &id
+		}
+
+		w.write(r)
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "key: %v\n", err)
+		w.close()
+		return exitFail
+	}
+
+	return w.close()
+}
+
+// The gen executes the gen command.
+func gen(args []string, stdout, stderr io.Writer) int {
+	var cfg config
+	var n int
+	var order, secret string
+	var offset uint64
+
+	fs := newFlagSet("gen", "csv", &cfg, stderr)
+	fs.IntVar(&n, "n", 0, "number of keys to generate")
+	fs.StringVar(&order, "order", "sequential",
+		"order of the keys: sequential, permuted or random;\n"+
+			"the permuted keys are unique for the same -secret\n"+
+			"across the files generated with the -offset")
+	fs.StringVar(&secret, "secret", "",
+		"secret of the permuted order")
+	fs.Uint64Var(&offset, "offset", 0,
+		"number of the keys generated before, to resume the generation")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	ls, err := cfg.locksmith()
+	if err != nil {
+		fmt.Fprintf(stderr, "key: %v\n", err)
+		return exitUsage
+	}
+
+	if n < 1 || fs.NArg() > 0 {
+		fmt.Fprintln(stderr, "key: the -n flag must be positive "+
+			"and there are no arguments")
+		return exitUsage
+	}
+
+	next, err := generator(ls, order, secret, offset, n)
+	if err != nil {
+		fmt.Fprintf(stderr, "key: %v\n", err)
+		return exitUsage
+	}
+
+	w := newWriter(cfg.format, "key", stdout, stderr)
+	w.columns = []string{"id", "key"}
+	for i := 0; i < n; i++ {
+		id, k, err := next()
+		if err != nil {
+			w.close()
+			fmt.Fprintf(stderr, "key: %v after %d keys\n", err, i)
+			return exitFail
+		}
+
+		w.write(record{ID: &id, Key: k})
+	}
+
+	if order != "random" {
+		fmt.Fprintf(stderr, "key: the next offset is %d\n",
+			offset+uint64(n))
+	}
+
+	return w.close()
+}
+
+// The generator returns the function that returns the next ID and key
+// of the order, the n is the number of keys to generate.
+func generator(
+	ls *key.Locksmith,
+	order, secret string,
+	offset uint64,
+	n int,
+) (func() (uint64, string, error), error) {
+	switch order {
+	case "sequential":
+		it, err := ls.Iterator()
+		if err == nil {
+			err = it.SeekID(offset)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		first := true
+		return func() (uint64, string, error) {
+			if !first && !it.Next() {
+				if err := it.Err(); err != nil {
+					return 0, "", err
+				}
+
+				return 0, "", errors.New("the key space is exhausted")
+			}
+
+			first = false
+			return it.ID(), it.Key(), nil
+		}, nil
+	case "permuted":
+		coupons, err := key.NewCoupons(ls, []byte(secret))
+		if err == nil {
+			err = coupons.Resume(offset)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return func() (uint64, string, error) {
+			k, id, err := coupons.Next()
+			return id, k, err
+		}, nil
+	case "random":
+		if offset != 0 {
+			return nil, errors.New("the random order can't be resumed")
+		}
+
+		keys, ids, err := ls.RandomN(n)
+		if err != nil {
+			return nil, err
+		}
+
+		i := -1
+		return func() (uint64, string, error) {
+			i++
+			return ids[i], keys[i], nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown order %q", order)
+}
+
+// The info executes the info command.
+func info(args []string, stdout, stderr io.Writer) int {
+	var cfg config
+	fs := newFlagSet("info", "plain", &cfg, stderr)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	ls, err := cfg.locksmith()
+	if err != nil {
+		fmt.Fprintf(stderr, "key: %v\n", err)
+		return exitUsage
+	}
+
+	total := strconv.FormatUint(ls.Total(), 10)
+	if ls.Total() == math.MaxUint64 {
+		total = "unlimited (uint64)"
+	}
+
+	size := strconv.FormatUint(ls.Size(), 10)
+	if ls.Size() == 0 {
+		size = "dynamic"
+	}
+
+	fields := [][2]string{
+		{"alphabet", ls.Alphabet()},
+		{"length", strconv.Itoa(len([]rune(ls.Alphabet())))},
+		{"size", size},
+		{"total", total},
+		{"sortable", strconv.FormatBool(ls.Sortable())},
+	}
+
+	if pattern := ls.Pattern(); pattern != nil {
+		fields = append(fields, [2]string{"pattern",
+			strings.Join(pattern, " ")})
+	}
+
+	switch cfg.format {
+	case "csv":
+		w := csv.NewWriter(stdout)
+		w.Write([]string{"field", "value"})
+		for _, f := range fields {
+			w.Write(f[:])
+		}
+		w.Flush()
+	case "json":
+		obj := make(map[string]string, len(fields))
+		for _, f := range fields {
+			obj[f[0]] = f[1]
+		}
+
+		data, _ := json.Marshal(obj)
+		fmt.Fprintf(stdout, "%s\n", data)
+	default:
+		for _, f := range fields {
+			fmt.Fprintf(stdout, "%-9s %s\n", f[0]+":", f[1])
+		}
+	}
+
+	return exitOK
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/goloop/key"
+)
+
+// The presets are the named alphabets of the -preset flag.
+var presets = map[string]string{
+	"base58":    key.Base58,
+	"base62":    key.Base62,
+	"crockford": key.Crockford32,
+	"hex":       "0123456789abcdef",
+	"base36":    "0123456789abcdefghijklmnopqrstuvwxyz",
+	"proquint":  "", // the pattern of the NewProquint function
+}
+
+// The config is the common flags of the commands.
+type config struct {
+	alphabet  string
+	size      int
+	preset    string
+	format    string
+	checksum  bool
+	group     int
+	separator string
+}
+
+// The newFlagSet returns the flag set of the command with the common
+// flags, the format is the default output format of the command, the
+// errors are written into the stderr.
+func newFlagSet(
+	name, format string,
+	cfg *config,
+	stderr io.Writer,
+) *flag.FlagSet {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&cfg.alphabet, "alphabet", "",
+		"alphabet of the keys")
+	fs.IntVar(&cfg.size, "size", 0,
+		"fixed size of the keys, 0 means dynamic size;\n"+
+			"for the proquint preset it's the number of 5-char chunks")
+	fs.StringVar(&cfg.preset, "preset", "",
+		"named alphabet: "+strings.Join(names, ", ")+
+			"\n(default base58 if -alphabet isn't set)")
+	fs.StringVar(&cfg.format, "format", format,
+		"output format: plain, csv or json (one object per line)")
+	fs.BoolVar(&cfg.checksum, "checksum", false,
+		"append the Luhn check character to the keys")
+	fs.IntVar(&cfg.group, "group", 0,
+		"split the keys into groups of the size, 0 means no groups")
+	fs.StringVar(&cfg.separator, "separator", "-",
+		"separator between the groups")
+
+	return fs
+}
+
+// The locksmith returns the Locksmith of the flags.
+func (cfg *config) locksmith() (*key.Locksmith, error) {
+	switch cfg.format {
+	case "plain", "csv", "json":
+	default:
+		return nil, fmt.Errorf("unknown format %q", cfg.format)
+	}
+
+	if cfg.alphabet != "" && cfg.preset != "" {
+		return nil, errors.New("the -alphabet and -preset flags " +
+			"are mutually exclusive")
+	}
+
+	alphabet := cfg.alphabet
+	if alphabet == "" {
+		if cfg.preset == "" {
+			cfg.preset = "base58"
+		}
+
+		var ok bool
+		if alphabet, ok = presets[cfg.preset]; !ok {
+			return nil, fmt.Errorf("unknown preset %q", cfg.preset)
+		}
+	}
+
+	var ls *key.Locksmith
+	var err error
+	if cfg.preset == "proquint" {
+		chunks := cfg.size
+		if chunks == 0 {
+			chunks = 4
+		}
+
+		ls, err = key.NewProquint(chunks)
+	} else {
+		ls, err = key.New(alphabet, cfg.size)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []key.Option
+	if cfg.checksum {
+		opts = append(opts, key.WithChecksum())
+	}
+
+	if cfg.group != 0 {
+		sep := []rune(cfg.separator)
+		if len(sep) != 1 {
+			return nil, fmt.Errorf("the separator must be one character, "+
+				"got %q", cfg.separator)
+		}
+
+		opts = append(opts, key.WithGroups(cfg.group, sep[0]))
+	}
+
+	return ls.With(opts...)
+}
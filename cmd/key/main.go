@@ -0,0 +1,76 @@
+// Command key encodes numeric IDs into keys and decodes keys back
+// into IDs with the github.com/goloop/key package.
+//
+// Usage:
+//
+//	key <command> [flags] [values...]
+//
+// The commands are:
+//
+//	encode  converts IDs into keys
+//	decode  converts keys into IDs
+//	gen     generates the keys, like the coupon codes
+//	info    prints the parameters of the key space
+//
+// The values are taken from the arguments, or from the standard input
+// line by line if there are no arguments. For example:
+//
+//	key encode -preset base62 -size 8 10 58
+//	cat keys.txt | key decode -preset base62 -size 8 -format csv
+//	key gen -size 8 -checksum -group 4 -n 1000 -order permuted -secret s
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// The usage is the help message of the command.
+const usage = `Usage: key <command> [flags] [values...]
+
+Commands:
+  encode  converts IDs into keys
+  decode  converts keys into IDs
+  gen     generates the keys, like the coupon codes
+  info    prints the parameters of the key space
+
+Run "key <command> -h" for the flags of the command.
+`
+
+// Exit codes of the command.
+const (
+	exitOK    = 0 // all values are processed
+	exitFail  = 1 // some values are invalid
+	exitUsage = 2 // invalid command or flags
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// The run executes the command with the arguments and returns
+// the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "encode":
+		return encode(args[1:], stdin, stdout, stderr)
+	case "decode":
+		return decode(args[1:], stdin, stdout, stderr)
+	case "gen":
+		return gen(args[1:], stdout, stderr)
+	case "info":
+		return info(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	}
+
+	fmt.Fprintf(stderr, "key: unknown command %q\n\n%s", args[0], usage)
+	return exitUsage
+}
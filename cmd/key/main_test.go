@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// The execute runs the command and returns its exit code and output.
+func execute(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// TestRun tests the commands.
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name  string
+		stdin string
+		args  []string
+		code  int
+		out   string
+	}{
+		{
+			"Encode arguments", "",
+			[]string{"encode", "-size", "8", "10", "58"},
+			exitOK, "1111111B\n11111121\n",
+		},
+		{
+			"Encode stdin", "10\n\n 58 \n",
+			[]string{"encode", "-preset", "base62", "-size", "4"},
+			exitOK, "000A\n000w\n",
+		},
+		{
+			"Encode invalid", "",
+			[]string{"encode", "-alphabet", "abc", "-size", "2", "9", "x"},
+			exitFail, "",
+		},
+		{
+			"Decode CSV", "1111111B\nzz\n",
+			[]string{"decode", "-size", "8", "-format", "csv"},
+			exitFail, "id,key,error\n10,1111111B,\n,zz,\"invalid key " +
+				"length, must be 8 char(s) but 2 char(s)\"\n",
+		},
+		{
+			"Decode JSON", "",
+			[]string{"decode", "-preset", "crockford", "-format", "json",
+				"A"},
+			exitOK, "{\"id\":10,\"key\":\"A\"}\n",
+		},
+		{
+			"Decode proquint", "",
+			[]string{"decode", "-preset", "proquint", "-size", "1",
+				"babap"},
+			exitOK, "10\n",
+		},
+		{
+			"Info", "",
+			[]string{"info", "-alphabet", "abc", "-size", "3"},
+			exitOK, "alphabet: abc\nlength:   3\nsize:     3\n" +
+				"total:    27\nsortable: true\n",
+		},
+		{
+			"Info JSON", "",
+			[]string{"info", "-preset", "hex", "-format", "json"},
+			exitOK, "{\"alphabet\":\"0123456789abcdef\",\"length\":\"16\"," +
+				"\"size\":\"dynamic\",\"sortable\":\"false\"," +
+				"\"total\":\"unlimited (uint64)\"}\n",
+		},
+		{"No command", "", nil, exitUsage, ""},
+		{"Unknown command", "", []string{"foo"}, exitUsage, ""},
+		{"Help", "", []string{"help"}, exitOK, usage},
+		{"Unknown flag", "", []string{"info", "-foo"}, exitUsage, ""},
+		{
+			"Unknown preset", "",
+			[]string{"info", "-preset", "foo"}, exitUsage, "",
+		},
+		{
+			"Unknown format", "",
+			[]string{"info", "-format", "xml"}, exitUsage, "",
+		},
+		{
+			"Preset and alphabet", "",
+			[]string{"info", "-preset", "hex", "-alphabet", "ab"},
+			exitUsage, "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, out, errs := execute(test.stdin, test.args...)
+			if code != test.code {
+				t.Errorf("exit code = %d, want %d (stderr: %s)",
+					code, test.code, errs)
+			}
+
+			if out != test.out {
+				t.Errorf("output = %q, want %q", out, test.out)
+			}
+		})
+	}
+}
+
+// TestRunErrors tests that the invalid values are reported
+// into the standard error in the plain format.
+func TestRunErrors(t *testing.T) {
+	code, out, errs := execute("", "encode", "-alphabet", "abc",
+		"-size", "2", "9", "x")
+	if code != exitFail || out != "" {
+		t.Errorf("exit code = %d, output = %q", code, out)
+	}
+
+	want := "key: 9 is large ID for key generation\nkey: invalid ID \"x\"\n"
+	if errs != want {
+		t.Errorf("stderr = %q, want %q", errs, want)
+	}
+}
+
+// TestGen tests the gen command.
+func TestGen(t *testing.T) {
+	code, out, errs := execute("", "gen", "-alphabet", "abc", "-size", "2",
+		"-n", "3", "-offset", "2")
+	if code != exitOK || out != "id,key\n2,ac\n3,ba\n4,bb\n" {
+		t.Errorf("exit code = %d, output = %q", code, out)
+	}
+
+	if errs != "key: the next offset is 5\n" {
+		t.Errorf("stderr = %q", errs)
+	}
+
+	code, out, _ = execute("", "gen", "-alphabet", "abc", "-size", "2",
+		"-n", "5", "-offset", "6", "-format", "plain")
+	if code != exitFail || out != "ca\ncb\ncc\n" {
+		t.Errorf("exit code = %d, output = %q", code, out)
+	}
+
+	for _, args := range [][]string{
+		{"gen"},
+		{"gen", "-n", "1", "extra"},
+		{"gen", "-n", "1", "-order", "foo"},
+		{"gen", "-n", "1", "-order", "permuted"},
+		{"gen", "-n", "1", "-order", "random", "-offset", "1"},
+		{"gen", "-n", "1", "-group", "2", "-separator", "--"},
+	} {
+		if code, _, _ := execute("", args...); code != exitUsage {
+			t.Errorf("%v: exit code = %d, want %d", args, code, exitUsage)
+		}
+	}
+}
+
+// TestGenUnique tests that the permuted and random keys are unique,
+// and the keys with checksum and groups can be decoded.
+func TestGenUnique(t *testing.T) {
+	flags := []string{"-alphabet", "0123456789", "-size", "3",
+		"-checksum", "-group", "2", "-format", "plain"}
+
+	var keys []string
+	for _, offset := range []string{"0", "400"} {
+		args := append([]string{"gen", "-n", "400", "-order", "permuted",
+			"-secret", "s", "-offset", offset}, flags...)
+		code, out, errs := execute("", args...)
+		if code != exitOK {
+			t.Fatalf("exit code = %d, stderr = %s", code, errs)
+		}
+
+		keys = append(keys, strings.Fields(out)...)
+	}
+
+	args := append([]string{"gen", "-n", "1000", "-order", "random"},
+		flags...)
+	_, out, _ := execute("", args...)
+	random := strings.Fields(out)
+
+	for _, list := range [][]string{keys, random} {
+		seen := make(map[string]bool)
+		for _, k := range list {
+			if seen[k] {
+				t.Fatalf("duplicate key %s", k)
+			}
+			seen[k] = true
+		}
+	}
+
+	if len(keys) != 800 || len(random) != 1000 {
+		t.Errorf("%d and %d keys, want 800 and 1000", len(keys),
+			len(random))
+	}
+
+	args = append([]string{"decode"}, flags...)
+	code, _, errs := execute(strings.Join(keys, "\n"), args...)
+	if code != exitOK {
+		t.Errorf("decode: exit code = %d, stderr = %s", code, errs)
+	}
+}
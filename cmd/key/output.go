@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// The record is the result of the conversion of one value.
+type record struct {
+	ID    *uint64 `json:"id,omitempty"`
+	Key   string  `json:"key,omitempty"`
+	Error string  `json:"error,omitempty"`
+}
+
+// The writer writes the records in the output format.
+type writer struct {
+	format  string
+	field   string // the field of the plain format: "id" or "key"
+	out     io.Writer
+	errs    io.Writer
+	csv     *csv.Writer
+	columns []string // columns of the csv format
+	header  bool     // true if the csv header is written
+	failed  bool     // true if some record has the error
+}
+
+// The newWriter returns a new writer of the records, the field is the
+// result of the conversion which is printed in the plain format.
+func newWriter(format, field string, out, errs io.Writer) *writer {
+	w := &writer{
+		format:  format,
+		field:   field,
+		out:     out,
+		errs:    errs,
+		columns: []string{"id", "key", "error"},
+	}
+
+	if format == "csv" {
+		w.csv = csv.NewWriter(out)
+	}
+
+	return w
+}
+
+// The writeHeader writes the csv header once.
+func (w *writer) writeHeader() {
+	if w.csv != nil && !w.header {
+		w.csv.Write(w.columns)
+		w.header = true
+	}
+}
+
+// The write writes the record.
+func (w *writer) write(r record) {
+	if r.Error != "" {
+		w.failed = true
+	}
+
+	switch w.format {
+	case "csv":
+		w.writeHeader()
+		row := make([]string, len(w.columns))
+		for i, column := range w.columns {
+			switch column {
+			case "id":
+				if r.ID != nil {
+					row[i] = strconv.FormatUint(*r.ID, 10)
+				}
+			case "key":
+				row[i] = r.Key
+			case "error":
+				row[i] = r.Error
+			}
+		}
+
+		w.csv.Write(row)
+	case "json":
+		data, _ := json.Marshal(r)
+		fmt.Fprintf(w.out, "%s\n", data)
+	default:
+		switch {
+		case r.Error != "":
+			fmt.Fprintf(w.errs, "key: %s\n", r.Error)
+		case w.field == "id":
+			fmt.Fprintln(w.out, *r.ID)
+		default:
+			fmt.Fprintln(w.out, r.Key)
+		}
+	}
+}
+
+// The close flushes the output and returns the exit code.
+func (w *writer) close() int {
+	if w.csv != nil {
+		w.writeHeader()
+		w.csv.Flush()
+	}
+
+	if w.failed {
+		return exitFail
+	}
+
+	return exitOK
+}
+
+// The values calls fn for the arguments, or for the non-blank lines
+// of the input if there are no arguments.
+func values(args []string, in io.Reader, fn func(string)) error {
+	if len(args) > 0 {
+		for _, arg := range args {
+			fn(arg)
+		}
+
+		return nil
+	}
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			fn(line)
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,392 @@
+package key
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Config is the serializable configuration of the Locksmith. It's
+// encoded in JSON as the object, and in the text formats as the
+// compact spec string, like "alphabet=abc;size=8;checksum=luhn".
+//
+// The spec is the list of the name=value fields separated by the ';'
+// character. The values are percent-encoded if they contain the '%',
+// ';', '=', ',', ':' characters or spaces. The fields are:
+//
+//   - alphabet - the alphabet of the New function;
+//   - size - the size of the key, zero by default;
+//   - pattern - the pattern of the NewPattern function;
+//   - classes - the classes of the pattern, like "c:bdf,v:aiou";
+//   - checksum - "luhn" for the WithChecksum option;
+//   - group and separator - the WithGroups option, the separator
+//     is "-" by default;
+//   - blocklist - the comma-separated words of the WithBlocklist option;
+//   - prefix - the prefix of the WithPrefix option.
+type Config struct {
+	Alphabet  string            `json:"alphabet,omitempty"`
+	Size      int               `json:"size,omitempty"`
+	Pattern   string            `json:"pattern,omitempty"`
+	Classes   map[string]string `json:"classes,omitempty"`
+	Checksum  string            `json:"checksum,omitempty"`
+	Group     int               `json:"group,omitempty"`
+	Separator string            `json:"separator,omitempty"`
+	Blocklist []string          `json:"blocklist,omitempty"`
+	Prefix    string            `json:"prefix,omitempty"`
+}
+
+// FromConfig returns a new Locksmith object of the configuration.
+// The configuration is validated like by the New, NewPattern functions
+// and the options.
+//
+// Example usage:
+//
+//	cfg, err := ParseSpec("alphabet=0123456789;size=8;checksum=luhn")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	ls, err := FromConfig(cfg)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func FromConfig(cfg Config) (*Locksmith, error) {
+	var ls *Locksmith
+	var err error
+
+	switch {
+	case cfg.Alphabet != "" && cfg.Pattern != "":
+		return &Locksmith{}, errors.New("the alphabet and pattern " +
+			"are mutually exclusive")
+	case cfg.Pattern != "":
+		if cfg.Size != 0 {
+			return &Locksmith{}, errors.New("the size of the patterned " +
+				"key is defined by the pattern")
+		}
+
+		var classes map[rune]string
+		if cfg.Classes != nil {
+			classes = make(map[rune]string, len(cfg.Classes))
+			for name, alphabet := range cfg.Classes {
+				r := []rune(name)
+				if len(r) != 1 {
+					return &Locksmith{}, fmt.Errorf("the %q class name "+
+						"must be one character", name)
+				}
+
+				classes[r[0]] = alphabet
+			}
+		}
+
+		ls, err = NewPattern(cfg.Pattern, classes)
+	default:
+		if len(cfg.Classes) != 0 {
+			return &Locksmith{}, errors.New("the classes require " +
+				"the pattern")
+		}
+
+		ls, err = New(cfg.Alphabet, cfg.Size)
+	}
+
+	if err != nil {
+		return &Locksmith{}, err
+	}
+
+	var opts []Option
+	switch cfg.Checksum {
+	case "", "none":
+	case "luhn":
+		opts = append(opts, WithChecksum())
+	default:
+		return &Locksmith{}, fmt.Errorf("unknown checksum %q", cfg.Checksum)
+	}
+
+	if cfg.Group != 0 || cfg.Separator != "" {
+		sep, err := separator(cfg.Separator)
+		if err != nil {
+			return &Locksmith{}, err
+		}
+
+		opts = append(opts, WithGroups(cfg.Group, sep))
+	}
+
+	if cfg.Blocklist != nil {
+		opts = append(opts, WithBlocklist(cfg.Blocklist...))
+	}
+
+	if cfg.Prefix != "" {
+		opts = append(opts, WithPrefix(cfg.Prefix))
+	}
+
+	return ls.With(opts...)
+}
+
+// The separator returns the group separator of the configuration.
+func separator(sep string) (rune, error) {
+	if sep == "" {
+		return '-', nil
+	}
+
+	r := []rune(sep)
+	if len(r) != 1 {
+		return 0, fmt.Errorf("the %q separator must be one character", sep)
+	}
+
+	return r[0], nil
+}
+
+// Config returns the configuration of the Locksmith, the FromConfig
+// function creates the same Locksmith from it.
+func (ls *Locksmith) Config() Config {
+	cfg := Config{}
+	if ls.pattern == nil {
+		cfg.Alphabet, cfg.Size = string(ls.alphabet), int(ls.size)
+	} else {
+		cfg.Pattern, cfg.Classes = ls.classes()
+	}
+
+	if ls.checksum {
+		cfg.Checksum = "luhn"
+	}
+
+	if ls.group != 0 {
+		cfg.Group, cfg.Separator = ls.group, string(ls.separator)
+	}
+
+	if ls.blocklist != nil {
+		cfg.Blocklist = append([]string(nil), ls.blocklist.words...)
+	}
+
+	cfg.Prefix = ls.prefix
+
+	return cfg
+}
+
+// The classes returns the pattern and classes of the patterned key,
+// the positions with the same alphabet share the class. The classes
+// of the DefaultClasses keep their names.
+func (ls *Locksmith) classes() (string, map[string]string) {
+	names := make(map[string]string) // alphabet => class name
+	for name, alphabet := range DefaultClasses() {
+		names[alphabet] = string(name)
+	}
+
+	const spare = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	pattern := make([]rune, 0, len(ls.pattern))
+	classes := make(map[string]string)
+	next := 0
+	for _, alphabet := range ls.Pattern() {
+		name, ok := names[alphabet]
+		if !ok {
+			name = string(rune(0x100 + next))
+			if next < len(spare) {
+				name = spare[next : next+1]
+			}
+
+			names[alphabet] = name
+			next++
+		}
+
+		classes[name] = alphabet
+		pattern = append(pattern, []rune(name)...)
+	}
+
+	return string(pattern), classes
+}
+
+// String returns the spec string of the configuration.
+func (cfg Config) String() string {
+	var fields []string
+	add := func(name, value string) {
+		fields = append(fields, name+"="+escape(value))
+	}
+
+	if cfg.Alphabet != "" {
+		add("alphabet", cfg.Alphabet)
+	}
+
+	if cfg.Size != 0 {
+		add("size", strconv.Itoa(cfg.Size))
+	}
+
+	if cfg.Pattern != "" {
+		add("pattern", cfg.Pattern)
+	}
+
+	if len(cfg.Classes) != 0 {
+		names := make([]string, 0, len(cfg.Classes))
+		for name := range cfg.Classes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		classes := make([]string, len(names))
+		for i, name := range names {
+			classes[i] = escape(name) + ":" + escape(cfg.Classes[name])
+		}
+
+		fields = append(fields, "classes="+strings.Join(classes, ","))
+	}
+
+	if cfg.Checksum != "" {
+		add("checksum", cfg.Checksum)
+	}
+
+	if cfg.Group != 0 {
+		add("group", strconv.Itoa(cfg.Group))
+	}
+
+	if cfg.Separator != "" {
+		add("separator", cfg.Separator)
+	}
+
+	if len(cfg.Blocklist) != 0 {
+		words := make([]string, len(cfg.Blocklist))
+		for i, word := range cfg.Blocklist {
+			words[i] = escape(word)
+		}
+
+		fields = append(fields, "blocklist="+strings.Join(words, ","))
+	}
+
+	if cfg.Prefix != "" {
+		add("prefix", cfg.Prefix)
+	}
+
+	return strings.Join(fields, ";")
+}
+
+// ParseSpec parses the spec string of the configuration,
+// see the Config type for the syntax.
+func ParseSpec(spec string) (Config, error) {
+	var cfg Config
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(spec, ";") {
+		if strings.TrimSpace(field) == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return Config{}, fmt.Errorf("invalid spec field %q", field)
+		}
+
+		name = strings.TrimSpace(name)
+		if seen[name] {
+			return Config{}, fmt.Errorf("the %s spec field is repeated", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "alphabet":
+			cfg.Alphabet, err = url.PathUnescape(value)
+		case "size":
+			cfg.Size, err = strconv.Atoi(value)
+		case "pattern":
+			cfg.Pattern, err = url.PathUnescape(value)
+		case "classes":
+			cfg.Classes = make(map[string]string)
+			for _, class := range strings.Split(value, ",") {
+				n, alphabet, ok := strings.Cut(class, ":")
+				if !ok {
+					return Config{}, fmt.Errorf("invalid class %q", class)
+				}
+
+				if n, err = url.PathUnescape(n); err != nil {
+					break
+				}
+
+				if cfg.Classes[n], err = url.PathUnescape(alphabet); err != nil {
+					break
+				}
+			}
+		case "checksum":
+			cfg.Checksum, err = url.PathUnescape(value)
+		case "group":
+			cfg.Group, err = strconv.Atoi(value)
+		case "separator":
+			cfg.Separator, err = url.PathUnescape(value)
+		case "blocklist":
+			for _, word := range strings.Split(value, ",") {
+				if word, err = url.PathUnescape(word); err != nil {
+					break
+				}
+
+				cfg.Blocklist = append(cfg.Blocklist, word)
+			}
+		case "prefix":
+			cfg.Prefix, err = url.PathUnescape(value)
+		default:
+			return Config{}, fmt.Errorf("unknown spec field %q", name)
+		}
+
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid %s spec field: %w",
+				name, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// MarshalText encodes the configuration as the spec string.
+func (cfg Config) MarshalText() ([]byte, error) {
+	return []byte(cfg.String()), nil
+}
+
+// UnmarshalText decodes the spec string of the configuration.
+func (cfg *Config) UnmarshalText(text []byte) error {
+	parsed, err := ParseSpec(string(text))
+	if err != nil {
+		return err
+	}
+
+	*cfg = parsed
+	return nil
+}
+
+// The plainConfig is the Config without methods,
+// it's encoded in JSON as the object.
+type plainConfig Config
+
+// MarshalJSON encodes the configuration as the JSON object.
+func (cfg Config) MarshalJSON() ([]byte, error) {
+	return json.Marshal(plainConfig(cfg))
+}
+
+// UnmarshalJSON decodes the configuration from the JSON object
+// or from the JSON string with the spec.
+func (cfg *Config) UnmarshalJSON(data []byte) error {
+	var spec string
+	if err := json.Unmarshal(data, &spec); err == nil {
+		return cfg.UnmarshalText([]byte(spec))
+	}
+
+	var plain plainConfig
+	if err := json.Unmarshal(data, &plain); err != nil {
+		return err
+	}
+
+	*cfg = Config(plain)
+	return nil
+}
+
+// The escape percent-encodes the special characters of the spec value.
+func escape(value string) string {
+	var sb strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case b <= ' ' || b == 0x7f || strings.IndexByte("%;=,:", b) >= 0:
+			fmt.Fprintf(&sb, "%%%02X", b)
+		default:
+			sb.WriteByte(b)
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,179 @@
+package key
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestConfigRoundTrip tests that the Locksmith is restored from its
+// configuration, the spec string and JSON.
+func TestConfigRoundTrip(t *testing.T) {
+	plain, _ := New("ab;c=d,e", 4)
+	grouped, _ := plain.With(WithChecksum(), WithGroups(2, ' '))
+	blocked, _ := New("abcdefgh")
+	blocked, _ = blocked.With(WithBlocklist("bad", "f4ce"))
+	proquint, _ := NewProquint(2)
+	prefixed, _ := plain.With(WithPrefix("o;_"))
+	custom, _ := NewPattern("xyyx", map[rune]string{
+		'x': "01", 'y': "abc",
+	})
+
+	tests := []struct {
+		name string
+		ls   *Locksmith
+	}{
+		{"Plain", plain},
+		{"Checksum and groups", grouped},
+		{"Blocklist", blocked},
+		{"Proquint", proquint},
+		{"Custom pattern", custom},
+		{"Prefix", prefixed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := test.ls.Config()
+
+			spec, err := ParseSpec(cfg.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(spec, cfg) {
+				t.Errorf("ParseSpec(%q) = %#v, want %#v",
+					cfg.String(), spec, cfg)
+			}
+
+			data, err := json.Marshal(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var decoded Config
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(decoded, cfg) {
+				t.Errorf("JSON %s = %#v, want %#v", data, decoded, cfg)
+			}
+
+			ls, err := FromConfig(decoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, id := range []uint64{0, 1, 10, 57} {
+				want, _ := test.ls.Marshal(id)
+				if got, _ := ls.Marshal(id); got != want {
+					t.Errorf("Marshal(%d) = %q, want %q", id, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestParseSpec tests the ParseSpec function.
+func TestParseSpec(t *testing.T) {
+	cfg, err := ParseSpec("alphabet=0123456789; size=8;checksum=luhn;" +
+		"group=4;blocklist=a%3Bb,c;")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Config{
+		Alphabet:  "0123456789",
+		Size:      8,
+		Checksum:  "luhn",
+		Group:     4,
+		Blocklist: []string{"a;b", "c"},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("ParseSpec() = %#v, want %#v", cfg, want)
+	}
+
+	for _, spec := range []string{
+		"alphabet",
+		"foo=bar",
+		"size=x",
+		"size=1;size=2",
+		"alphabet=%zz",
+		"pattern=cv;classes=c",
+	} {
+		if _, err := ParseSpec(spec); err == nil {
+			t.Errorf("ParseSpec(%q) should return an error", spec)
+		}
+	}
+}
+
+// TestConfigJSON tests that the configuration is decoded
+// from the JSON string with the spec.
+func TestConfigJSON(t *testing.T) {
+	var v struct {
+		Orders Config `json:"orders"`
+		Users  Config `json:"users"`
+	}
+
+	data := `{"orders":"alphabet=abc;size=3",` +
+		`"users":{"pattern":"cvc","group":2}}`
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Orders.Alphabet != "abc" || v.Orders.Size != 3 {
+		t.Errorf("orders = %#v", v.Orders)
+	}
+
+	if v.Users.Pattern != "cvc" || v.Users.Group != 2 {
+		t.Errorf("users = %#v", v.Users)
+	}
+
+	if err := json.Unmarshal([]byte(`{"orders":"x"}`), &v); err == nil {
+		t.Error("json.Unmarshal() of invalid spec should return an error")
+	}
+}
+
+// TestFromConfig tests that the FromConfig validates the configuration.
+func TestFromConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"Blank alphabet", Config{}},
+		{"Duplicate char", Config{Alphabet: "aba"}},
+		{"Negative size", Config{Alphabet: "ab", Size: -1}},
+		{"Alphabet and pattern", Config{Alphabet: "ab", Pattern: "cv"}},
+		{"Pattern and size", Config{Pattern: "cv", Size: 2}},
+		{"Unknown class", Config{Pattern: "cx"}},
+		{"Long class name", Config{Pattern: "c",
+			Classes: map[string]string{"cc": "ab"}}},
+		{"Classes without pattern", Config{Alphabet: "ab",
+			Classes: map[string]string{"c": "ab"}}},
+		{"Unknown checksum", Config{Alphabet: "ab", Checksum: "crc"}},
+		{"Checksum of pattern", Config{Pattern: "cv", Checksum: "luhn"}},
+		{"Bad group", Config{Alphabet: "ab", Group: -1}},
+		{"Long separator", Config{Alphabet: "ab", Group: 2,
+			Separator: "--"}},
+		{"Separator in alphabet", Config{Alphabet: "ab", Group: 2,
+			Separator: "a"}},
+		{"Blank blocklist", Config{Alphabet: "ab", Blocklist: []string{}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := FromConfig(test.cfg); err == nil {
+				t.Error("FromConfig() should return an error")
+			}
+		})
+	}
+
+	ls, err := FromConfig(Config{Alphabet: "abc", Size: 4, Group: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key, _ := ls.Marshal(10); key != "ab-ab" {
+		t.Errorf("Marshal(10) = %q, want %q", key, "ab-ab")
+	}
+}
@@ -0,0 +1,129 @@
+package key
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Coupons is a generator of unique non-sequential keys, like coupon
+// or voucher codes.
+//
+// The generator walks the keyed permutation of the key space of the
+// Locksmith (see Permutation), so the keys look random, but each key is
+// generated only once for the same secret. The state of the generator
+// is the number of generated keys only - the checkpoint, which can be
+// saved and used to resume the generation later without duplicates
+// across batches.
+//
+// The generator is safe for concurrent use by multiple goroutines.
+// It should be created by the NewCoupons function only.
+type Coupons struct {
+	ls   *Locksmith
+	perm *Permutation
+
+	mu   sync.Mutex
+	next uint64 // checkpoint, i.e. number of generated keys
+}
+
+// NewCoupons returns a new generator of unique keys from the key space
+// of the ls Locksmith shuffled by the secret. The secret must be kept
+// the same for all batches.
+//
+// Example usage:
+//
+//	ls, _ := New("23456789ABCDEFGHJKLMNPQRSTUVWXYZ", 8)
+//	coupons, err := NewCoupons(ls, []byte("secret"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	coupons.Resume(checkpoint) // the saved state of the previous batch
+//	keys, ids, err := coupons.Batch(100000)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	checkpoint = coupons.Checkpoint()
+func NewCoupons(ls *Locksmith, secret []byte) (*Coupons, error) {
+	if ls == nil {
+		return &Coupons{}, errors.New("nil Locksmith")
+	}
+
+	perm, err := NewPermutation(ls.Total(), secret)
+	if err != nil {
+		return &Coupons{}, err
+	}
+
+	return &Coupons{ls: ls, perm: perm}, nil
+}
+
+// Checkpoint returns the number of generated keys, it's the state
+// of the generator for the Resume method.
+func (c *Coupons) Checkpoint() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.next
+}
+
+// Resume sets the number of generated keys, the generation continues
+// from the checkpoint. The checkpoint must be less than the number of
+// keys, i.e. the key space must have keys left.
+//
+// For the whole uint64 range (see Total method of the Locksmith) the
+// last key of the permutation isn't generated, since the checkpoint
+// after it doesn't fit into uint64, so MaxUint64 keys are available.
+func (c *Coupons) Resume(checkpoint uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if checkpoint >= c.perm.n {
+		return fmt.Errorf("the %d checkpoint is out of range", checkpoint)
+	}
+
+	c.next = checkpoint
+	return nil
+}
+
+// Next returns the next unique key and its ID.
+func (c *Coupons) Next() (string, uint64, error) {
+	keys, ids, err := c.Batch(1)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return keys[0], ids[0], nil
+}
+
+// Batch returns the next n unique keys and their IDs.
+//
+// If the key space doesn't contain n more keys, the error is returned
+// and the checkpoint isn't changed.
+func (c *Coupons) Batch(n int) ([]string, []uint64, error) {
+	if n < 0 {
+		return nil, nil, fmt.Errorf("incorrect number of keys %d", n)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The checkpoint can't wrap around, so the whole uint64 range
+	// has MaxUint64 keys too.
+	if uint64(n) > c.perm.n-c.next {
+		return nil, nil, fmt.Errorf("the key space has %d keys left, "+
+			"can't generate %d keys", c.perm.n-c.next, n)
+	}
+
+	keys, ids := make([]string, n), make([]uint64, n)
+	for i := range keys {
+		id := c.perm.Apply(c.next + uint64(i))
+		key, err := c.ls.Marshal(id)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		keys[i], ids[i] = key, id
+	}
+
+	c.next += uint64(n)
+	return keys, ids, nil
+}
@@ -0,0 +1,139 @@
+package key
+
+import (
+	"math"
+	"testing"
+)
+
+// TestNewCoupons tests the NewCoupons function.
+func TestNewCoupons(t *testing.T) {
+	ls, _ := New("0123456789", 3)
+
+	if _, err := NewCoupons(nil, []byte("secret")); err == nil {
+		t.Error("NewCoupons(nil) should return an error")
+	}
+
+	if _, err := NewCoupons(ls, nil); err == nil {
+		t.Error("NewCoupons() with blank secret should return an error")
+	}
+}
+
+// TestCouponsBatch tests that the batches don't contain duplicates,
+// aren't sequential and the space exhaustion is detected.
+func TestCouponsBatch(t *testing.T) {
+	ls, _ := New("0123456789", 3)
+	c, err := NewCoupons(ls, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(map[string]bool, 1000)
+	sequential := 0
+	for batch := 0; batch < 4; batch++ {
+		keys, ids, err := c.Batch(250)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, key := range keys {
+			if seen[key] {
+				t.Fatalf("duplicate key %q", key)
+			}
+			seen[key] = true
+
+			if id, _ := ls.Unmarshal(key); id != ids[i] {
+				t.Errorf("key %q has ID %d, want %d", key, id, ids[i])
+			}
+
+			if i > 0 && ids[i] == ids[i-1]+1 {
+				sequential++
+			}
+		}
+	}
+
+	if sequential > 50 {
+		t.Errorf("%d of 1000 keys are sequential", sequential)
+	}
+
+	if got := c.Checkpoint(); got != 1000 {
+		t.Errorf("Checkpoint() = %d, want 1000", got)
+	}
+
+	if _, _, err := c.Next(); err == nil {
+		t.Error("Next() should return an error when the space is exhausted")
+	}
+}
+
+// TestCouponsResume tests that the generation continues from the checkpoint.
+func TestCouponsResume(t *testing.T) {
+	ls, _ := New(Base58, 8)
+	secret := []byte("secret")
+
+	a, _ := NewCoupons(ls, secret)
+	want, _, _ := a.Batch(20)
+
+	b, _ := NewCoupons(ls, secret)
+	first, _, _ := b.Batch(10)
+	checkpoint := b.Checkpoint()
+
+	c, _ := NewCoupons(ls, secret)
+	if err := c.Resume(checkpoint); err != nil {
+		t.Fatal(err)
+	}
+	second, _, _ := c.Batch(10)
+
+	got := append(first, second...)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("key %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if err := c.Resume(ls.Total() + 1); err == nil {
+		t.Error("Resume() should return an error for out of range checkpoint")
+	}
+}
+
+// TestCouponsResumeEnd tests that the generation near the end of the
+// whole uint64 range doesn't wrap around to the first keys.
+func TestCouponsResumeEnd(t *testing.T) {
+	ls, _ := New(Base58)
+	secret := []byte("secret")
+
+	a, _ := NewCoupons(ls, secret)
+	first, _, _ := a.Batch(3)
+
+	c, _ := NewCoupons(ls, secret)
+	if err := c.Resume(math.MaxUint64 - 3); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := c.Batch(5); err == nil {
+		t.Error("Batch() should return an error when the space is exhausted")
+	}
+
+	if got := c.Checkpoint(); got != math.MaxUint64-3 {
+		t.Errorf("Checkpoint() = %d, want %d", got, uint64(math.MaxUint64-3))
+	}
+
+	last, _, err := c.Batch(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range last {
+		for _, other := range first {
+			if key == other {
+				t.Errorf("the %q key is generated twice", key)
+			}
+		}
+	}
+
+	if _, _, err := c.Next(); err == nil {
+		t.Error("Next() should return an error when the space is exhausted")
+	}
+
+	if err := c.Resume(math.MaxUint64); err == nil {
+		t.Error("Resume() should return an error for exhausted checkpoint")
+	}
+}
@@ -0,0 +1,126 @@
+package key
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The sentinel errors of the package. The returned errors wrap them,
+// so they should be checked by the errors.Is function:
+//
+//	id, err := ls.Unmarshal(key)
+//	switch {
+//	case errors.Is(err, ErrInvalidLength), errors.Is(err, ErrInvalidChar):
+//	    // the malformed key, respond with 400 Bad Request
+//	case errors.Is(err, ErrOverflow):
+//	    // the key is out of the key space, respond with 404 Not Found
+//	}
+var (
+	// ErrEmptyAlphabet is returned when the alphabet or the pattern
+	// of the Locksmith is blank.
+	ErrEmptyAlphabet = errors.New("blank alphabet string")
+
+	// ErrDuplicateChar is returned when the alphabet or the class
+	// of the pattern contains the same character twice, or the wordlist
+	// of the Wordsmith contains the same word twice.
+	ErrDuplicateChar = errors.New("duplicate char in the alphabet")
+
+	// ErrNegativeSize is returned when the size of the key is negative.
+	ErrNegativeSize = errors.New("incorrect size")
+
+	// ErrIDOutOfRange is returned when the ID is greater than or
+	// equal to the total number of keys, it doesn't fit into the
+	// integer type, or all its keys are blocked by the blocklist.
+	// The *RangeError matches it too.
+	ErrIDOutOfRange = errors.New("ID is out of range")
+
+	// ErrInvalidLength is returned when the key has the wrong number
+	// of characters (or words of the Wordsmith).
+	ErrInvalidLength = errors.New("invalid key length")
+
+	// ErrInvalidChar is returned when the key contains a character
+	// that isn't allowed at its position, including the wrong prefix,
+	// separator, check character (or check word) and the tweak character
+	// of the blocklist. The *InvalidCharError and *InvalidWordError
+	// match it too.
+	ErrInvalidChar = errors.New("invalid char in the key")
+
+	// ErrOverflow is returned when the key is out of the key space,
+	// or the result of the key arithmetic is out of the key space
+	// (greater than or equal to Total, or less than zero).
+	ErrOverflow = errors.New("key overflow")
+
+	// ErrNonCanonical is returned by the Validate method when the key
+	// is valid but isn't the canonical one, the Normalize method returns
+	// its canonical form. The Normalize method returns it for the key
+	// with the wrong tweak character of the blocklist.
+	ErrNonCanonical = errors.New("non-canonical key")
+)
+
+// InvalidCharError is returned when the key contains a character that
+// isn't set in the alphabet. It matches the ErrInvalidChar error.
+//
+// Example usage:
+//
+//	_, err := ls.Unmarshal("ab!")
+//	var ice *InvalidCharError
+//	if errors.As(err, &ice) {
+//	    fmt.Println(ice.Rune, ice.Position) // Output: 33 2
+//	}
+type InvalidCharError struct {
+	Rune     rune // the invalid character
+	Position int  // index of the character in the key, in runes
+}
+
+// Error returns the error message.
+func (e *InvalidCharError) Error() string {
+	return fmt.Sprintf("key contains a char that isn't "+
+		"set in the alphabet: %c", e.Rune)
+}
+
+// Is returns true for the ErrInvalidChar error.
+func (e *InvalidCharError) Is(target error) bool {
+	return target == ErrInvalidChar
+}
+
+// InvalidWordError is returned when the key of the Wordsmith contains
+// a word that isn't set in the wordlist. It matches the ErrInvalidChar
+// error.
+type InvalidWordError struct {
+	Word     string // the invalid word
+	Position int    // index of the word in the key
+}
+
+// Error returns the error message.
+func (e *InvalidWordError) Error() string {
+	return fmt.Sprintf("key contains a word that isn't "+
+		"set in the wordlist: %q", e.Word)
+}
+
+// Is returns true for the ErrInvalidChar error.
+func (e *InvalidWordError) Is(target error) bool {
+	return target == ErrInvalidChar
+}
+
+// The wrapError is the error with its own message that
+// wraps the sentinel error.
+type wrapError struct {
+	msg string
+	err error
+}
+
+// Error returns the error message.
+func (e *wrapError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the sentinel error.
+func (e *wrapError) Unwrap() error {
+	return e.err
+}
+
+// The errorf formats the error message like the fmt.Errorf function,
+// the result wraps the err sentinel error.
+func errorf(err error, format string, a ...any) error {
+	return &wrapError{msg: fmt.Sprintf(format, a...), err: err}
+}
@@ -0,0 +1,308 @@
+package key
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestErrorsNew tests the sentinel errors of the constructors.
+func TestErrorsNew(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{
+			name: "blank alphabet",
+			err:  func() error { _, err := New(""); return err }(),
+			want: ErrEmptyAlphabet,
+		},
+		{
+			name: "blank pattern",
+			err:  func() error { _, err := NewPattern("", nil); return err }(),
+			want: ErrEmptyAlphabet,
+		},
+		{
+			name: "negative size",
+			err:  func() error { _, err := New("abc", -1); return err }(),
+			want: ErrNegativeSize,
+		},
+		{
+			name: "repeated char",
+			err:  func() error { _, err := New("abca"); return err }(),
+			want: ErrDuplicateChar,
+		},
+		{
+			name: "repeated char of class",
+			err: func() error {
+				_, err := NewPattern("x", map[rune]string{'x': "abb"})
+				return err
+			}(),
+			want: ErrDuplicateChar,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, tt.want) {
+				t.Errorf("expected %v but %v", tt.want, tt.err)
+			}
+		})
+	}
+
+	// The messages are kept.
+	_, err := New("abca")
+	if err.Error() != "the a item is repeated in the alphabet" {
+		t.Errorf("unexpected message: %s", err)
+	}
+}
+
+// TestErrorsMarshal tests the sentinel errors of the Marshal methods.
+func TestErrorsMarshal(t *testing.T) {
+	ls, _ := New("abc", 3)
+
+	if _, err := ls.Marshal(27); !errors.Is(err, ErrIDOutOfRange) {
+		t.Errorf("expected ErrIDOutOfRange but %v", err)
+	}
+
+	if _, err := ls.MarshalInt64(14); !errors.Is(err, ErrIDOutOfRange) {
+		t.Errorf("expected ErrIDOutOfRange but %v", err)
+	}
+
+	if _, err := MarshalOf(ls, -1); !errors.Is(err, ErrIDOutOfRange) {
+		t.Errorf("expected ErrIDOutOfRange but %v", err)
+	}
+
+	key, _ := ls.Marshal(26)
+	if _, err := UnmarshalAs[int8](ls, key); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	big, _ := New("abc")
+	key, _ = big.Marshal(300)
+	if _, err := UnmarshalAs[uint8](big, key); !errors.Is(err,
+		ErrIDOutOfRange) {
+		t.Errorf("expected ErrIDOutOfRange but %v", err)
+	}
+
+	if _, err := ls.Add("ccc", 1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expected ErrOverflow but %v", err)
+	}
+
+	last, _ := big.Marshal(math.MaxUint64)
+	if _, err := big.Add(last, 1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expected ErrOverflow but %v", err)
+	}
+
+	if _, err := ls.Sub("aab", 2); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expected ErrOverflow but %v", err)
+	}
+}
+
+// TestErrorsUnmarshal tests the sentinel errors of the Unmarshal methods.
+func TestErrorsUnmarshal(t *testing.T) {
+	ls, _ := New("abc", 3)
+	dynamic, _ := New("abc")
+	grouped, _ := ls.With(WithGroups(2, '-'), WithPrefix("k_"))
+	checked, _ := New("0123456789", 4)
+	checked, _ = checked.With(WithChecksum())
+
+	tests := []struct {
+		name string
+		ls   *Locksmith
+		key  string
+		want error
+	}{
+		{"short key", ls, "ab", ErrInvalidLength},
+		{"long key", ls, "abca", ErrInvalidLength},
+		{"unknown char", ls, "abd", ErrInvalidChar},
+		{"overflow", dynamic, strings.Repeat("c", 41), ErrOverflow},
+		{"no prefix", grouped, "ab-ca", ErrInvalidChar},
+		{"bad separator", grouped, "k_ab+ca", ErrInvalidChar},
+		{"bad check char", checked, "12340", ErrInvalidChar},
+		{"no check char", checked, "1", ErrInvalidLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.ls.Unmarshal(tt.key)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v but %v", tt.want, err)
+			}
+		})
+	}
+
+	uuid, _ := New(Base62)
+	if _, err := uuid.UnmarshalUUID("abc"); !errors.Is(err,
+		ErrInvalidLength) {
+		t.Errorf("expected ErrInvalidLength but %v", err)
+	}
+}
+
+// TestInvalidCharError tests the InvalidCharError type.
+func TestInvalidCharError(t *testing.T) {
+	ls, _ := New("abc", 3)
+	grouped, _ := New("abcd", 4)
+	grouped, _ = grouped.With(WithGroups(2, '-'), WithPrefix("ключ_"))
+	checked, _ := New("0123456789", 4)
+	checked, _ = checked.With(WithChecksum())
+	uuid, _ := New(Base62)
+
+	tests := []struct {
+		name     string
+		err      error
+		char     rune
+		position int
+	}{
+		{
+			name:     "plain",
+			err:      func() error { _, err := ls.Unmarshal("ab!"); return err }(),
+			char:     '!',
+			position: 2,
+		},
+		{
+			name: "grouped",
+			err: func() error {
+				_, err := grouped.Unmarshal("ключ_ab-cx")
+				return err
+			}(),
+			char:     'x',
+			position: 9,
+		},
+		{
+			name: "checksum",
+			err: func() error {
+				_, err := checked.Unmarshal("12x40")
+				return err
+			}(),
+			char:     'x',
+			position: 2,
+		},
+		{
+			name: "uuid",
+			err: func() error {
+				_, err := uuid.UnmarshalUUID("7RKE2sawAICsEsyZKHWW6!")
+				return err
+			}(),
+			char:     '!',
+			position: 21,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ice *InvalidCharError
+			if !errors.As(tt.err, &ice) {
+				t.Fatalf("expected *InvalidCharError but %v", tt.err)
+			}
+
+			if ice.Rune != tt.char || ice.Position != tt.position {
+				t.Errorf("expected %q at %d but %q at %d",
+					tt.char, tt.position, ice.Rune, ice.Position)
+			}
+
+			if !errors.Is(tt.err, ErrInvalidChar) {
+				t.Errorf("expected ErrInvalidChar but %v", tt.err)
+			}
+		})
+	}
+
+	_, err := ls.Unmarshal("ab!")
+	if err.Error() != "key contains a char that isn't set "+
+		"in the alphabet: !" {
+		t.Errorf("unexpected message: %s", err)
+	}
+}
+
+// TestErrorsBlocklist tests the sentinel errors of the blocklist.
+func TestErrorsBlocklist(t *testing.T) {
+	ls, _ := New("abc", 3)
+	ls, _ = ls.With(WithBlocklist("cab"))
+	dynamic, _ := New("abc")
+	dynamic, _ = dynamic.With(WithBlocklist("cab"))
+
+	tests := []struct {
+		name string
+		ls   *Locksmith
+		key  string
+		want error
+	}{
+		{"non-canonical tweak", ls, "baab", ErrInvalidChar},
+		{"too short", ls, "a", ErrInvalidLength},
+		{"wrong length", ls, "aaaaa", ErrInvalidLength},
+		{"unknown char", ls, "aa!a", ErrInvalidChar},
+		{"overflow", dynamic, "a" + strings.Repeat("c", 41), ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.ls.Unmarshal(tt.key); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v but %v", tt.want, err)
+			}
+		})
+	}
+
+	// All keys contain the banned words.
+	blocked, _ := New("ab", 1)
+	blocked, _ = blocked.With(WithBlocklist("a", "b"))
+	if _, err := blocked.Marshal(0); !errors.Is(err, ErrIDOutOfRange) {
+		t.Errorf("expected ErrIDOutOfRange but %v", err)
+	}
+}
+
+// TestErrorsWordsmith tests the sentinel errors of the Wordsmith.
+func TestErrorsWordsmith(t *testing.T) {
+	words := []string{"apple", "banana", "cherry"}
+	ws, _ := NewWordsmith(words, 2)
+	checked, _ := NewWordsmith(words, 2, WordChecksum())
+	dynamic, _ := NewWordsmith(words, 0)
+
+	if _, err := NewWordsmith(words, -1); !errors.Is(err, ErrNegativeSize) {
+		t.Errorf("expected ErrNegativeSize but %v", err)
+	}
+
+	_, err := NewWordsmith([]string{"apple", "apple"}, 1)
+	if !errors.Is(err, ErrDuplicateChar) {
+		t.Errorf("expected ErrDuplicateChar but %v", err)
+	}
+
+	if _, err := ws.Marshal(9); !errors.Is(err, ErrIDOutOfRange) {
+		t.Errorf("expected ErrIDOutOfRange but %v", err)
+	}
+
+	tests := []struct {
+		name string
+		ws   *Wordsmith
+		key  string
+		want error
+	}{
+		{"wrong length", ws, "apple", ErrInvalidLength},
+		{"unknown word", ws, "apple-grape", ErrInvalidChar},
+		{"no check word", checked, "apple", ErrInvalidLength},
+		{"bad check word", checked, "apple-banana-apple", ErrInvalidChar},
+		{"overflow", dynamic,
+			strings.TrimSuffix(strings.Repeat("cherry-", 41), "-"),
+			ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.ws.Unmarshal(tt.key); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v but %v", tt.want, err)
+			}
+		})
+	}
+
+	var iwe *InvalidWordError
+	_, err = ws.Unmarshal("apple-grape")
+	if !errors.As(err, &iwe) || iwe.Word != "grape" || iwe.Position != 1 {
+		t.Errorf("expected \"grape\" at 1 but %v", err)
+	}
+
+	if err.Error() != "key contains a word that isn't set "+
+		`in the wordlist: "grape"` {
+		t.Errorf("unexpected message: %s", err)
+	}
+}
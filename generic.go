@@ -0,0 +1,92 @@
+package key
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Integer is a constraint that permits any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// RangeError is returned when the ID doesn't fit into the integer type
+// or into the key space of the Locksmith.
+type RangeError struct {
+	Value string // the ID as a decimal number
+	Type  string // name of the integer type, empty for the key space
+}
+
+// Error returns the error message.
+func (e *RangeError) Error() string {
+	if e.Type == "" {
+		return fmt.Sprintf("%s is out of range of the key space", e.Value)
+	}
+
+	return fmt.Sprintf("%s is out of range of %s", e.Value, e.Type)
+}
+
+// Is returns true for the ErrIDOutOfRange error.
+func (e *RangeError) Is(target error) bool {
+	return target == ErrIDOutOfRange
+}
+
+// MarshalOf converts an ID of any integer type into a key.
+//
+// The ID must be non-negative and less than the total number of
+// possible keys, otherwise the *RangeError is returned. To convert
+// negative IDs use the MarshalInt64 method.
+//
+// Example usage:
+//
+//	ls, _ := New("abc")
+//	key, err := MarshalOf(ls, int32(10))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(key) // Output: "bab"
+func MarshalOf[T Integer](ls *Locksmith, id T) (string, error) {
+	if id < 0 {
+		return "", &RangeError{Value: strconv.FormatInt(int64(id), 10)}
+	}
+
+	if !ls.fits(uint64(id)) {
+		return "", &RangeError{Value: strconv.FormatUint(uint64(id), 10)}
+	}
+
+	return ls.Marshal(uint64(id))
+}
+
+// UnmarshalAs decodes a key and returns its corresponding ID as
+// the value of the T integer type.
+//
+// If the decoded ID doesn't fit into the T type the *RangeError
+// is returned.
+//
+// Example usage:
+//
+//	ls, _ := New("abc")
+//	id, err := UnmarshalAs[uint8](ls, "bab")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(id) // Output: 10
+func UnmarshalAs[T Integer](ls *Locksmith, key string) (T, error) {
+	id, err := ls.Unmarshal(key)
+	if err != nil {
+		return 0, err
+	}
+
+	// The conversion is lossless if the value is converted back
+	// to the same number and doesn't become negative.
+	v := T(id)
+	if v < 0 || uint64(v) != id {
+		return 0, &RangeError{
+			Value: strconv.FormatUint(id, 10),
+			Type:  fmt.Sprintf("%T", v),
+		}
+	}
+
+	return v, nil
+}
@@ -0,0 +1,76 @@
+package key
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// TestMarshalOf tests MarshalOf function.
+func TestMarshalOf(t *testing.T) {
+	ls, _ := New("abc", 3)
+
+	if key, err := MarshalOf(ls, int32(10)); err != nil || key != "bab" {
+		t.Errorf("expected %q but %q (%v)", "bab", key, err)
+	}
+
+	if key, err := MarshalOf(ls, uint8(13)); err != nil || key != "bbb" {
+		t.Errorf("expected %q but %q (%v)", "bbb", key, err)
+	}
+
+	type userID int64
+	if key, err := MarshalOf(ls, userID(26)); err != nil || key != "ccc" {
+		t.Errorf("expected %q but %q (%v)", "ccc", key, err)
+	}
+
+	var re *RangeError
+	for _, err := range []error{
+		func() error { _, err := MarshalOf(ls, -1); return err }(),
+		func() error { _, err := MarshalOf(ls, int8(math.MinInt8)); return err }(),
+		func() error { _, err := MarshalOf(ls, uint16(27)); return err }(),
+	} {
+		if !errors.As(err, &re) {
+			t.Errorf("expected *RangeError but %v", err)
+		}
+	}
+
+	if re.Error() != "27 is out of range of the key space" {
+		t.Errorf("unexpected message: %s", re.Error())
+	}
+}
+
+// TestUnmarshalAs tests UnmarshalAs function.
+func TestUnmarshalAs(t *testing.T) {
+	ls, _ := New("abcdefghijklmnopqrstuvwxyz")
+
+	key, _ := ls.Marshal(300)
+	if id, err := UnmarshalAs[int16](ls, key); err != nil || id != 300 {
+		t.Errorf("expected 300 but %d (%v)", id, err)
+	}
+
+	var re *RangeError
+	if _, err := UnmarshalAs[uint8](ls, key); !errors.As(err, &re) {
+		t.Fatalf("expected *RangeError but %v", err)
+	}
+
+	if re.Error() != "300 is out of range of uint8" {
+		t.Errorf("unexpected message: %s", re.Error())
+	}
+
+	// The value that becomes negative.
+	key, _ = ls.Marshal(math.MaxInt64 + 1)
+	if _, err := UnmarshalAs[int64](ls, key); !errors.As(err, &re) {
+		t.Errorf("expected *RangeError but %v", err)
+	}
+
+	if id, err := UnmarshalAs[uint64](ls, key); err != nil ||
+		id != math.MaxInt64+1 {
+		t.Errorf("expected %d but %d (%v)", uint64(math.MaxInt64+1), id, err)
+	}
+
+	// The errors of the Unmarshal method are returned as is.
+	if _, err := UnmarshalAs[int](ls, "ABC"); err == nil ||
+		errors.As(err, &re) {
+		t.Errorf("expected the Unmarshal error but %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package key
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Binder binds the ID type to the Locksmith that encodes its values.
+// It's implemented by the empty types, one per kind of ID:
+//
+//	var orders, _ = New(Base58, 8)
+//
+//	type Orders struct{}
+//
+//	func (Orders) Locksmith() *Locksmith { return orders }
+type Binder interface {
+	Locksmith() *Locksmith
+}
+
+// ID is the numeric ID that is encoded as the key of the Locksmith of
+// the B binder in the text formats. It implements the TextMarshaler and
+// TextUnmarshaler interfaces of the encoding package, so it's encoded
+// as the key by the encoding/json, encoding/xml and other packages, and
+// the fmt.Stringer interface.
+//
+// Example usage:
+//
+//	type Order struct {
+//	    ID   ID[Orders] `json:"id"`
+//	    Name string     `json:"name"`
+//	}
+//
+//	data, _ := json.Marshal(Order{ID: 10, Name: "book"})
+//	fmt.Println(string(data)) // Output: {"id":"1111111B","name":"book"}
+type ID[B Binder] uint64
+
+// The locksmith returns the Locksmith of the binder.
+func locksmith[B Binder]() (*Locksmith, error) {
+	var b B
+	if ls := b.Locksmith(); ls != nil {
+		return ls, nil
+	}
+
+	return nil, fmt.Errorf("the %T binder has no Locksmith", b)
+}
+
+// String returns the key of the ID, or the "%!key(ID)" string
+// if the ID can't be encoded.
+func (id ID[B]) String() string {
+	text, err := id.MarshalText()
+	if err != nil {
+		return fmt.Sprintf("%%!key(%d)", uint64(id))
+	}
+
+	return string(text)
+}
+
+// MarshalText encodes the ID as the key.
+func (id ID[B]) MarshalText() ([]byte, error) {
+	ls, err := locksmith[B]()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ls.Marshal(uint64(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(key), nil
+}
+
+// UnmarshalText decodes the key into the ID.
+func (id *ID[B]) UnmarshalText(text []byte) error {
+	ls, err := locksmith[B]()
+	if err != nil {
+		return err
+	}
+
+	v, err := ls.Unmarshal(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid key %q: %w", text, err)
+	}
+
+	*id = ID[B](v)
+	return nil
+}
+
+// MarshalJSON encodes the ID as the JSON string of the key.
+func (id ID[B]) MarshalJSON() ([]byte, error) {
+	text, err := id.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON decodes the JSON string of the key into the ID,
+// the null value doesn't change the ID.
+func (id *ID[B]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return errors.New("the key must be a JSON string")
+	}
+
+	return id.UnmarshalText([]byte(text))
+}
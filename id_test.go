@@ -0,0 +1,108 @@
+package key
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// The testOrders is the Locksmith of the testOrder binder.
+var testOrders, _ = New(Base58, 8)
+
+// testOrder binds the ID to the testOrders Locksmith.
+type testOrder struct{}
+
+// Locksmith returns the Locksmith of the binder.
+func (testOrder) Locksmith() *Locksmith { return testOrders }
+
+// testNoLocksmith is the binder without Locksmith.
+type testNoLocksmith struct{}
+
+// Locksmith returns nil.
+func (testNoLocksmith) Locksmith() *Locksmith { return nil }
+
+// TestIDJSON tests the JSON encoding of the ID.
+func TestIDJSON(t *testing.T) {
+	type order struct {
+		ID   ID[testOrder]  `json:"id"`
+		Ref  *ID[testOrder] `json:"ref"`
+		Name string         `json:"name"`
+	}
+
+	data, err := json.Marshal(order{ID: 10, Name: "book"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"id":"1111111B","ref":null,"name":"book"}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	var got order
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.ID != 10 || got.Ref != nil {
+		t.Errorf("json.Unmarshal() = %+v", got)
+	}
+
+	err = json.Unmarshal([]byte(`{"id":"1111111I"}`), &got)
+	if err == nil {
+		t.Error("json.Unmarshal() of invalid key should return an error")
+	}
+
+	err = json.Unmarshal([]byte(`{"id":10}`), &got)
+	if err == nil {
+		t.Error("json.Unmarshal() of number should return an error")
+	}
+}
+
+// TestIDText tests the text encoding of the ID.
+func TestIDText(t *testing.T) {
+	type order struct {
+		ID ID[testOrder] `xml:"id,attr"`
+	}
+
+	data, err := xml.Marshal(order{ID: 58})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `<order id="11111121"></order>`; string(data) != want {
+		t.Errorf("xml.Marshal() = %s, want %s", data, want)
+	}
+
+	var got order
+	if err := xml.Unmarshal(data, &got); err != nil || got.ID != 58 {
+		t.Errorf("xml.Unmarshal() = %d, %v", got.ID, err)
+	}
+
+	// The error of the Locksmith is wrapped.
+	var id ID[testOrder]
+	err = id.UnmarshalText([]byte("short"))
+	_, lsErr := testOrders.Unmarshal("short")
+	if err == nil || errors.Unwrap(err) == nil ||
+		errors.Unwrap(err).Error() != lsErr.Error() {
+		t.Errorf("UnmarshalText() error = %v, want wrapped %v", err, lsErr)
+	}
+}
+
+// TestIDString tests the String method of the ID.
+func TestIDString(t *testing.T) {
+	if got := fmt.Sprint(ID[testOrder](10)); got != "1111111B" {
+		t.Errorf("String() = %q, want %q", got, "1111111B")
+	}
+
+	if got := ID[testNoLocksmith](10).String(); got != "%!key(10)" {
+		t.Errorf("String() = %q, want %q", got, "%!key(10)")
+	}
+
+	var id ID[testNoLocksmith]
+	if err := id.UnmarshalText([]byte("a")); err == nil {
+		t.Error("UnmarshalText() without Locksmith should return an error")
+	}
+}
@@ -0,0 +1,214 @@
+package key
+
+import (
+	"errors"
+	"math"
+)
+
+// Iterator walks the keys of the Locksmith in the order of IDs.
+//
+// The iterator increments the digits of the current key with a carry
+// instead of the conversion of each ID into a key, so the walk is cheap.
+// If the Locksmith has a blocklist, the keys are generated by the Marshal
+// method, because the tweaked keys don't follow the order of digits.
+//
+// The Iterator isn't safe for concurrent use by multiple goroutines.
+// It should be created by the Iterator method of the Locksmith only.
+type Iterator struct {
+	ls    *Locksmith
+	id    uint64 // ID of the current key
+	key   string // current key
+	value []int  // digits of the current key, nil for the blocklist
+	err   error  // error of the last move
+}
+
+// Iterator returns a new iterator positioned at the key of the zero ID.
+//
+// Example usage:
+//
+//	ls, _ := New("abc", 3)
+//	it, err := ls.Iterator()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	to, _ := ls.Unmarshal("bcc")
+//	for ok := it.Seek("baa") == nil; ok && it.ID() <= to; ok = it.Next() {
+//	    fmt.Println(it.ID(), it.Key()) // 9 baa, 10 bab, ..., 17 bcc
+//	}
+func (ls *Locksmith) Iterator() (*Iterator, error) {
+	it := &Iterator{ls: ls}
+	if err := it.SeekID(0); err != nil {
+		return &Iterator{}, err
+	}
+
+	return it, nil
+}
+
+// ID returns the ID of the current key.
+func (it *Iterator) ID() uint64 {
+	return it.id
+}
+
+// Key returns the current key.
+func (it *Iterator) Key() string {
+	return it.key
+}
+
+// Err returns the error of the last move of the iterator, it's possible
+// if the Locksmith has a blocklist and all keys of the ID are blocked.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// SeekID moves the iterator to the key of the ID.
+func (it *Iterator) SeekID(id uint64) error {
+	it.err = nil
+	if it.ls.blocklist != nil {
+		key, err := it.ls.Marshal(id)
+		if err != nil {
+			it.err = err
+			return err
+		}
+
+		it.id, it.key = id, key
+		return nil
+	}
+
+	if !it.ls.fits(id) {
+		return errorf(ErrIDOutOfRange,
+			"%d is large ID for key generation", id)
+	}
+
+	it.id, it.value = id, it.ls.split(id)
+	it.key = it.ls.format(it.value)
+	return nil
+}
+
+// Seek moves the iterator to the key.
+func (it *Iterator) Seek(key string) error {
+	id, err := it.ls.Unmarshal(key)
+	if err != nil {
+		return err
+	}
+
+	return it.SeekID(id)
+}
+
+// Next moves the iterator to the next key. It returns false if the
+// current key is the last one or on error (see the Err method),
+// the iterator isn't moved.
+func (it *Iterator) Next() bool {
+	if it.id == it.ls.last() {
+		return false
+	}
+
+	if it.value == nil {
+		return it.SeekID(it.id+1) == nil
+	}
+
+	i := len(it.value) - 1
+	for ; i >= 0; i-- {
+		it.value[i]++
+		if uint64(it.value[i]) < it.ls.base(i) {
+			break
+		}
+
+		it.value[i] = 0
+	}
+
+	// The carry out of the dynamic size key adds a new digit.
+	if i < 0 {
+		it.value = append([]int{1}, it.value...)
+	}
+
+	it.id++
+	it.key = it.ls.format(it.value)
+	return true
+}
+
+// Prev moves the iterator to the previous key. It returns false if
+// the current key is the first one or on error (see the Err method),
+// the iterator isn't moved.
+func (it *Iterator) Prev() bool {
+	if it.id == 0 {
+		return false
+	}
+
+	if it.value == nil {
+		return it.SeekID(it.id-1) == nil
+	}
+
+	for i := len(it.value) - 1; i >= 0; i-- {
+		if it.value[i] > 0 {
+			it.value[i]--
+			break
+		}
+
+		it.value[i] = int(it.ls.base(i)) - 1
+	}
+
+	// The dynamic size key has no leading zero digits.
+	if it.ls.size == 0 && len(it.value) > 1 && it.value[0] == 0 {
+		it.value = it.value[1:]
+	}
+
+	it.id--
+	it.key = it.ls.format(it.value)
+	return true
+}
+
+// Range calls the fn function for the keys of the IDs in the range
+// [from, to] in the order of IDs. The iteration stops if fn returns
+// false.
+//
+// Example usage:
+//
+//	ls, _ := New("abc", 3)
+//	from, _ := ls.Unmarshal("baa")
+//	to, _ := ls.Unmarshal("bcc")
+//	err := ls.Range(from, to, func(id uint64, key string) bool {
+//	    fmt.Println(id, key) // 9 baa, 10 bab, ..., 17 bcc
+//	    return true
+//	})
+func (ls *Locksmith) Range(from, to uint64,
+	fn func(id uint64, key string) bool) error {
+	it, err := ls.seek(from, to)
+	if err != nil {
+		return err
+	}
+
+	for fn(it.id, it.key) && it.id < to && it.Next() {
+	}
+
+	return it.Err()
+}
+
+// The seek returns a new iterator at the from ID of the [from, to] range.
+func (ls *Locksmith) seek(from, to uint64) (*Iterator, error) {
+	if from > to {
+		return nil, errors.New("the start of the range is greater " +
+			"than the end")
+	}
+
+	if !ls.fits(to) {
+		return nil, errorf(ErrIDOutOfRange,
+			"%d is large ID for key generation", to)
+	}
+
+	it := &Iterator{ls: ls}
+	if err := it.SeekID(from); err != nil {
+		return nil, err
+	}
+
+	return it, nil
+}
+
+// The last returns the largest ID of the key space.
+func (ls *Locksmith) last() uint64 {
+	if ls.total == math.MaxUint64 {
+		return math.MaxUint64
+	}
+
+	return ls.total - 1
+}
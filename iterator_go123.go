@@ -0,0 +1,23 @@
+//go:build go1.23
+
+package key
+
+import "iter"
+
+// Keys returns the iterator over the IDs and keys in the range
+// [from, to] in the order of IDs, for use with the range statement.
+//
+// If the range is invalid or the key of some ID can't be generated,
+// the iteration stops, use the Range method to get the error.
+//
+// Example usage:
+//
+//	ls, _ := New("abc", 3)
+//	for id, key := range ls.Keys(9, 17) {
+//	    fmt.Println(id, key) // 9 baa, 10 bab, ..., 17 bcc
+//	}
+func (ls *Locksmith) Keys(from, to uint64) iter.Seq2[uint64, string] {
+	return func(yield func(uint64, string) bool) {
+		ls.Range(from, to, yield)
+	}
+}
@@ -0,0 +1,26 @@
+//go:build go1.23
+
+package key
+
+import "testing"
+
+// TestKeys tests the Keys method.
+func TestKeys(t *testing.T) {
+	ls, _ := New("abc", 3)
+
+	var keys []string
+	for id, key := range ls.Keys(9, 17) {
+		if want, _ := ls.Marshal(id); key != want {
+			t.Errorf("key of %d = %q, want %q", id, key, want)
+		}
+
+		keys = append(keys, key)
+		if len(keys) == 3 {
+			break
+		}
+	}
+
+	if len(keys) != 3 || keys[0] != "baa" || keys[2] != "bac" {
+		t.Errorf("Keys() = %v", keys)
+	}
+}
@@ -0,0 +1,135 @@
+package key
+
+import (
+	"math"
+	"testing"
+)
+
+// TestIteratorNext tests that the Next and Prev methods walk the same
+// keys as generated by the Marshal method.
+func TestIteratorNext(t *testing.T) {
+	plain, _ := New("abc", 3)
+	dynamic, _ := New("abc")
+	grouped, _ := plain.With(WithGroups(2, '-'))
+	blocked, _ := dynamic.With(WithBlocklist("cab"))
+	proquint, _ := NewProquint(1)
+
+	tests := []struct {
+		name string
+		ls   *Locksmith
+		n    uint64
+	}{
+		{"Fixed size", plain, 27},
+		{"Dynamic size", dynamic, 100},
+		{"Groups", grouped, 27},
+		{"Blocklist", blocked, 100},
+		{"Pattern", proquint, 200},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			it, err := test.ls.Iterator()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for id := uint64(0); id < test.n; id++ {
+				want, _ := test.ls.Marshal(id)
+				if it.ID() != id || it.Key() != want {
+					t.Fatalf("got %d %q, want %d %q",
+						it.ID(), it.Key(), id, want)
+				}
+
+				if id+1 < test.n && !it.Next() {
+					t.Fatalf("Next() at %d returned false", id)
+				}
+			}
+
+			for id := test.n - 1; id > 0; id-- {
+				if !it.Prev() {
+					t.Fatalf("Prev() at %d returned false", id)
+				}
+
+				want, _ := test.ls.Marshal(id - 1)
+				if it.ID() != id-1 || it.Key() != want {
+					t.Fatalf("got %d %q, want %d %q",
+						it.ID(), it.Key(), id-1, want)
+				}
+			}
+
+			if it.Prev() {
+				t.Error("Prev() at the first key should return false")
+			}
+		})
+	}
+}
+
+// TestIteratorBounds tests the iterator at the ends of the key space.
+func TestIteratorBounds(t *testing.T) {
+	ls, _ := New("abc", 3)
+	it, _ := ls.Iterator()
+
+	if err := it.Seek("ccc"); err != nil {
+		t.Fatal(err)
+	}
+
+	if it.Next() || it.Key() != "ccc" {
+		t.Errorf("Next() at the last key moved to %q", it.Key())
+	}
+
+	if err := it.SeekID(27); err == nil {
+		t.Error("SeekID(27) should return an error")
+	}
+
+	if err := it.Seek("abd"); err == nil {
+		t.Error("Seek(\"abd\") should return an error")
+	}
+
+	dynamic, _ := New("abc")
+	it, _ = dynamic.Iterator()
+	if err := it.SeekID(math.MaxUint64); err != nil {
+		t.Fatal(err)
+	}
+
+	if it.Next() {
+		t.Error("Next() at MaxUint64 should return false")
+	}
+}
+
+// TestRange tests the Range method.
+func TestRange(t *testing.T) {
+	ls, _ := New("abc", 3)
+	from, _ := ls.Unmarshal("baa")
+	to, _ := ls.Unmarshal("bcc")
+
+	var keys []string
+	err := ls.Range(from, to, func(id uint64, key string) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != 9 || keys[0] != "baa" || keys[8] != "bcc" {
+		t.Errorf("Range() = %v", keys)
+	}
+
+	count := 0
+	ls.Range(0, 26, func(id uint64, key string) bool {
+		count++
+		return count < 5
+	})
+	if count != 5 {
+		t.Errorf("Range() didn't stop, called %d times", count)
+	}
+
+	all := func(uint64, string) bool { return true }
+	if err := ls.Range(5, 4, all); err == nil {
+		t.Error("Range(5, 4) should return an error")
+	}
+
+	if err := ls.Range(0, 27, all); err == nil {
+		t.Error("Range(0, 27) should return an error")
+	}
+}
@@ -1,10 +1,10 @@
 package key
 
 import (
-	"errors"
-	"fmt"
 	"math"
+	"math/bits"
 	"strings"
+	"unicode/utf8"
 )
 
 // New returns a new Locksmith object. It takes in three arguments:
@@ -44,7 +44,7 @@ func New(alphabet string, args ...int) (*Locksmith, error) {
 
 	// The alphabet must contain at least one character.
 	if len(alphabet) == 0 {
-		return &Locksmith{}, errors.New("blank alphabet string")
+		return &Locksmith{}, ErrEmptyAlphabet
 	}
 
 	// Size is a sum of all arguments.
@@ -54,7 +54,7 @@ func New(alphabet string, args ...int) (*Locksmith, error) {
 	}
 
 	if size < 0 {
-		return &Locksmith{}, errors.New("incorrect size")
+		return &Locksmith{}, ErrNegativeSize
 	}
 
 	// Create a pointer to the Locksmith object.
@@ -80,7 +80,7 @@ func New(alphabet string, args ...int) (*Locksmith, error) {
 		// Check the presence of duplicates in the alphabet.
 		// The alphabet shouldn't contain duplicates.
 		if _, ok := locksmith.indexOf[char]; ok {
-			return &Locksmith{}, fmt.Errorf(
+			return &Locksmith{}, errorf(ErrDuplicateChar,
 				"the %c item is repeated in the alphabet",
 				char,
 			)
@@ -97,8 +97,8 @@ func New(alphabet string, args ...int) (*Locksmith, error) {
 	// size of the alphabet, and S is the size of the key. But and
 	// this value is limited to MaxUint64 too.
 	if locksmith.size != 0 {
-		l, s := len(locksmith.alphabet), int(locksmith.size)
-		if total := uint64(pow(l, s)); total < math.MaxUint64 {
+		l, s := uint64(len(locksmith.alphabet)), int(locksmith.size)
+		if total, ok := capacity(l, s); ok {
 			locksmith.total = total
 		}
 	}
@@ -109,10 +109,23 @@ func New(alphabet string, args ...int) (*Locksmith, error) {
 // Locksmith is a key generation object.
 // It can be created correctly through the New function only.
 type Locksmith struct {
-	size     uint64       // length of the generated key
-	total    uint64       // maximum allowable key value
-	alphabet []rune       // list of characters to generate the key
-	indexOf  map[rune]int // the map of matching characters of alphabet
+	size      uint64       // length of the generated key
+	total     uint64       // maximum allowable key value
+	alphabet  []rune       // list of characters to generate the key
+	indexOf   map[rune]int // the map of matching characters of alphabet
+	pattern   []*position  // alphabets of the key positions, nil if plain
+	group     int          // number of characters in a group, 0 if disabled
+	separator rune         // separator between groups of characters
+	blocklist *blocklist   // list of banned words, nil if disabled
+	checksum  bool         // true if the check character is appended
+	prefix    string       // constant prefix of the keys
+}
+
+// The position describes the characters allowed at some position
+// of the key, i.e. the radix and digits of the mixed-radix number.
+type position struct {
+	chars   []rune       // list of characters of the position
+	indexOf map[rune]int // the map of matching characters of position
 }
 
 // Alphabet returns current alphabet value.
@@ -130,6 +143,10 @@ func (ls *Locksmith) Size() uint64 {
 // For example, for "abc" alphabet and key size as 3 - can be
 // created the 27 iterations: aaa, aab, aac, ..., cca, ccb, ccc.
 // So can be used indexs as 0 <= ID < 27 to generate a key.
+//
+// If the number of keys doesn't fit into uint64 (including the dynamic
+// size of the key) the method returns MaxUint64, in this case any
+// uint64 value can be used as ID.
 func (ls *Locksmith) Total() uint64 {
 	return ls.total
 }
@@ -161,32 +178,16 @@ func (ls *Locksmith) Total() uint64 {
 //	}
 //	fmt.Println(key) // Output: "bab"
 func (ls *Locksmith) Marshal(id uint64) (string, error) {
-	var result string
-
-	if id >= ls.Total() {
-		return "", fmt.Errorf("%d is large ID for key generation", id)
-	}
-
-	// Create key.
-	al := len(ls.alphabet)
-	l, r := int(id/uint64(al)), int(math.Mod(float64(id), float64(al)))
-	result = string(ls.alphabet[r])
-	for l >= al {
-		l, r = l/al, int(math.Mod(float64(l), float64(al)))
-		result = string(ls.alphabet[r]) + result
-	}
-
-	// If there is a balance only.
-	if l != 0 {
-		result = string(ls.alphabet[l]) + result
+	if !ls.fits(id) {
+		return "", errorf(ErrIDOutOfRange,
+			"%d is large ID for key generation", id)
 	}
 
-	// Create the right size wrench.
-	if repeat := int(ls.size) - len(result); ls.size > 0 && repeat > 0 {
-		result = strings.Repeat(string(ls.alphabet[0]), repeat) + result
+	if ls.blocklist != nil {
+		return ls.censor(id)
 	}
 
-	return result, nil
+	return ls.format(ls.split(id)), nil
 }
 
 // Unmarshal decodes a key and returns its corresponding ID.
@@ -210,27 +211,279 @@ func (ls *Locksmith) Marshal(id uint64) (string, error) {
 //	}
 //	fmt.Println(id) // Output: 10
 func (ls *Locksmith) Unmarshal(key string) (uint64, error) {
-	value := []rune(key)
+	if ls.plain() {
+		return ls.decode(key)
+	}
+
+	value, err := ls.parse(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if ls.blocklist != nil {
+		return ls.uncensor(key, value)
+	}
+
+	id, ok := ls.join(value)
+	if !ok {
+		return 0, errorf(ErrOverflow, "the %q key is out of range", key)
+	}
+
+	return id, nil
+}
+
+// The plain returns true if the key is the bare digits of the ID,
+// i.e. there is no pattern, prefix, groups, checksum and blocklist.
+func (ls *Locksmith) plain() bool {
+	return ls.pattern == nil && ls.blocklist == nil && ls.group == 0 &&
+		!ls.checksum && ls.prefix == ""
+}
+
+// The decode converts the plain key into the ID without the allocation
+// of the digits, it's the fast path of the Unmarshal method.
+func (ls *Locksmith) decode(key string) (uint64, error) {
+	if ls.size > 0 {
+		if l := utf8.RuneCountInString(key); uint64(l) != ls.size {
+			return 0, errorf(ErrInvalidLength, "invalid key length, "+
+				"must be %d char(s) but %d char(s)", ls.size, l)
+		}
+	}
+
+	// All characters are checked before the overflow is reported,
+	// like by the parse and join methods.
+	base := uint64(len(ls.alphabet))
+	id, ok, i := uint64(0), true, 0
+	for _, char := range key {
+		index, found := ls.indexOf[char]
+		if !found {
+			return 0, &InvalidCharError{Rune: char, Position: i}
+		}
+
+		if ok {
+			hi, lo := bits.Mul64(id, base)
+			sum, carry := bits.Add64(lo, uint64(index), 0)
+			id, ok = sum, hi == 0 && carry == 0
+		}
+
+		i++
+	}
+
+	if !ok || !ls.fits(id) {
+		return 0, errorf(ErrOverflow, "the %q key is out of range", key)
+	}
+
+	return id, nil
+}
+
+// The fits returns true if the ID can be converted into a key.
+func (ls *Locksmith) fits(id uint64) bool {
+	return id < ls.total || ls.total == math.MaxUint64
+}
+
+// The base returns the radix of the digit at the i position of the key.
+func (ls *Locksmith) base(i int) uint64 {
+	if ls.pattern != nil {
+		return uint64(len(ls.pattern[i].chars))
+	}
+
+	return uint64(len(ls.alphabet))
+}
+
+// The split converts an ID into the digits of the key.
+func (ls *Locksmith) split(id uint64) []int {
+	if ls.pattern == nil {
+		return digits(id, uint64(len(ls.alphabet)), int(ls.size))
+	}
+
+	// Each position of the patterned key has its own radix,
+	// the least significant digit is the last one.
+	result := make([]int, len(ls.pattern))
+	for i := len(result) - 1; i >= 0; i-- {
+		base := ls.base(i)
+		result[i], id = int(id%base), id/base
+	}
+
+	return result
+}
+
+// The join converts the digits of the key back into an ID.
+// It returns false if the value is out of range.
+func (ls *Locksmith) join(value []int) (uint64, bool) {
+	if ls.pattern == nil {
+		id, ok := number(value, uint64(len(ls.alphabet)))
+		return id, ok && ls.fits(id)
+	}
+
+	var id uint64
+	for i, d := range value {
+		hi, lo := bits.Mul64(id, ls.base(i))
+		if hi != 0 {
+			return 0, false
+		}
+
+		sum, carry := bits.Add64(lo, uint64(d), 0)
+		if carry != 0 {
+			return 0, false
+		}
+
+		id = sum
+	}
+
+	return id, ls.fits(id)
+}
+
+// The digit returns the characters allowed at the i position of
+// the key. The tweak character of the blocklist is at zero position
+// and uses the same characters as the first digit of the ID.
+func (ls *Locksmith) digit(i int) ([]rune, map[rune]int) {
+	if ls.blocklist != nil && i > 0 {
+		i--
+	}
+
+	if ls.pattern != nil {
+		return ls.pattern[i].chars, ls.pattern[i].indexOf
+	}
+
+	return ls.alphabet, ls.indexOf
+}
+
+// The format converts the digits into the key string.
+func (ls *Locksmith) format(value []int) string {
+	if ls.checksum {
+		l := len(value)
+		value = append(value[:l:l], luhn(value, len(ls.alphabet)))
+	}
+
+	result := make([]rune, 0, len(value)+len(value)/(ls.group+1))
+	for i, d := range value {
+		if ls.group > 0 && i > 0 && i%ls.group == 0 {
+			result = append(result, ls.separator)
+		}
+
+		chars, _ := ls.digit(i)
+		result = append(result, chars[d])
+	}
+
+	return ls.prefix + string(result)
+}
+
+// The parse converts the key string into the digits.
+func (ls *Locksmith) parse(key string) ([]int, error) {
+	key, err := ls.unprefix(key)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := ls.ungroup([]rune(key))
+	if err != nil {
+		return nil, err
+	}
+
+	value, err = ls.verify(value)
+	if err != nil {
+		return nil, err
+	}
 
 	// The key is the wrong size.
-	if l := uint64(len(value)); ls.size > 0 && l != ls.size {
-		return 0, fmt.Errorf("invalid key length, "+
+	l := uint64(len(value))
+	if ls.blocklist != nil {
+		if l < 2 {
+			return nil, errorf(ErrInvalidLength, "the key is too short")
+		}
+
+		l-- // the tweak character isn't included in the size
+	}
+
+	if ls.size > 0 && l != ls.size {
+		return nil, errorf(ErrInvalidLength, "invalid key length, "+
 			"must be %d char(s) but %d char(s)", ls.size, l)
 	}
 
-	id, value := uint64(0), reverse(unlead(ls.alphabet[0], value))
-	alphabetLength := len(ls.alphabet)
+	result := make([]int, len(value))
+	for i, char := range value {
+		_, indexOf := ls.digit(i)
+		index, ok := indexOf[char]
+		if !ok {
+			return nil, &InvalidCharError{Rune: char, Position: ls.offset(i)}
+		}
+
+		result[i] = index
+	}
+
+	return result, nil
+}
+
+// The unprefix removes the prefix of the key.
+func (ls *Locksmith) unprefix(key string) (string, error) {
+	if !strings.HasPrefix(key, ls.prefix) {
+		return "", errorf(ErrInvalidChar,
+			"the key must start with the %q prefix", ls.prefix)
+	}
+
+	return key[len(ls.prefix):], nil
+}
+
+// The verify checks and removes the check character of the key,
+// if the checksum is enabled.
+func (ls *Locksmith) verify(value []rune) ([]rune, error) {
+	if !ls.checksum {
+		return value, nil
+	}
+
+	if len(value) < 2 {
+		return nil, errorf(ErrInvalidLength, "the key is too short "+
+			"to contain a check character")
+	}
+
+	digits := make([]int, len(value))
 	for i, char := range value {
 		index, ok := ls.indexOf[char]
 		if !ok {
-			return 0, fmt.Errorf("key contains a char that isn't "+
-				"set in the alphabet: %c", char)
+			return nil, &InvalidCharError{Rune: char, Position: ls.offset(i)}
 		}
 
-		// Replacing math.Pow with custom pow.
-		power := pow(alphabetLength, i)
-		id += uint64(index * power)
+		digits[i] = index
 	}
 
-	return id, nil
+	last := len(value) - 1
+	if luhn(digits[:last], len(ls.alphabet)) != digits[last] {
+		return nil, errorf(ErrInvalidChar, "invalid check character")
+	}
+
+	return value[:last], nil
+}
+
+// The offset returns the position in the full key of the i character
+// of the key without the prefix and separators, in runes.
+func (ls *Locksmith) offset(i int) int {
+	result := utf8.RuneCountInString(ls.prefix) + i
+	if ls.group > 0 {
+		result += i / ls.group
+	}
+
+	return result
+}
+
+// The ungroup removes the separators between groups of characters,
+// the separator must be after each full group only.
+func (ls *Locksmith) ungroup(value []rune) ([]rune, error) {
+	if ls.group == 0 {
+		return value, nil
+	}
+
+	result := make([]rune, 0, len(value))
+	for i, char := range value {
+		if (i+1)%(ls.group+1) == 0 {
+			if char != ls.separator || i == len(value)-1 {
+				return nil, errorf(ErrInvalidChar, "invalid separator "+
+					"at %d position of the key", i)
+			}
+
+			continue
+		}
+
+		result = append(result, char)
+	}
+
+	return result, nil
 }
@@ -263,3 +263,78 @@ func TestUnmarshal(t *testing.T) {
 		t.Errorf("Expected id to be %d, got %d", expectedId, id)
 	}
 }
+
+// TestMarshalLarge tests Marshal and Unmarshal methods with
+// the large IDs and the keys that don't fit into uint64.
+func TestMarshalLarge(t *testing.T) {
+	ls, err := New("abcdefghijklmnopqrstuvwxyz0123456789")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []uint64{1<<53 + 1, math.MaxUint64 - 1, math.MaxUint64} {
+		key, err := ls.Marshal(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := ls.Unmarshal(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result != id {
+			t.Errorf("expected %d but %d", id, result)
+		}
+	}
+
+	// The 36^13 doesn't fit into uint64.
+	ls, err = New("abcdefghijklmnopqrstuvwxyz0123456789", 13)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ls.Total() != math.MaxUint64 {
+		t.Errorf("expected total to be MaxUint64, got %d", ls.Total())
+	}
+
+	if _, err := ls.Unmarshal("9999999999999"); err == nil {
+		t.Error("expected an error for the key out of range")
+	}
+}
+
+// TestUnmarshalAllocs tests that the plain keys are decoded without
+// the allocation and the same as by the general path.
+func TestUnmarshalAllocs(t *testing.T) {
+	ls, _ := New(Base58, 8)
+	key, _ := ls.Marshal(123456789)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := ls.Unmarshal(key); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	if allocs != 0 {
+		t.Errorf("expected no allocations but %v", allocs)
+	}
+
+	for _, ls := range []*Locksmith{ls, func() *Locksmith {
+		ls, _ := New("abc")
+		return ls
+	}()} {
+		for _, k := range []string{key, "", "bab", "aaab", "1a!", "cccc"} {
+			id, err := ls.decode(k)
+			value, perr := ls.parse(k)
+			want, ok := uint64(0), false
+			if perr == nil {
+				want, ok = ls.join(value)
+			}
+
+			if (err == nil) != (perr == nil && ok) || id != want {
+				t.Errorf("%q: expected %d (%v) but %d (%v)",
+					k, want, perr, id, err)
+			}
+		}
+	}
+}
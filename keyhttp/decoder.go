@@ -0,0 +1,253 @@
+package keyhttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/goloop/key"
+)
+
+// Source is the part of the request that contains the parameter.
+type Source int
+
+const (
+	// Path is the path parameter, like the {id} wildcard of the
+	// http.ServeMux pattern.
+	Path Source = iota
+
+	// Query is the parameter of the URL query string.
+	Query
+)
+
+// Param describes the request parameter that contains the key.
+type Param struct {
+	Name      string         // name of the parameter
+	Source    Source         // part of the request with the parameter
+	Locksmith *key.Locksmith // Locksmith that decodes the key
+	Optional  bool           // true if the parameter can be absent
+}
+
+// PathParam returns the required path parameter.
+func PathParam(name string, ls *key.Locksmith) Param {
+	return Param{Name: name, Source: Path, Locksmith: ls}
+}
+
+// QueryParam returns the required query parameter.
+func QueryParam(name string, ls *key.Locksmith) Param {
+	return Param{Name: name, Source: Query, Locksmith: ls}
+}
+
+// ParamError is the error of decoding of the request parameter.
+type ParamError struct {
+	Param  string // name of the parameter
+	Key    string // value of the parameter, empty if it's absent
+	Status int    // HTTP status code of the response
+	Err    error  // error of the Locksmith, nil if it's absent
+}
+
+// Error returns the error message.
+func (e *ParamError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("the %s parameter is required", e.Param)
+	}
+
+	return fmt.Sprintf("invalid %s parameter %q: %v", e.Param, e.Key, e.Err)
+}
+
+// Unwrap returns the error of the Locksmith.
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// Option is the option of the Decoder.
+type Option func(*Decoder) error
+
+// WithStatus sets the HTTP status code of the response to the request
+// with an invalid key, it's 404 Not Found by default, since the key
+// that can't be decoded doesn't identify any resource. The code must
+// be a client error code, like 400 Bad Request. The request without
+// a required parameter always gets the 400 Bad Request response.
+func WithStatus(code int) Option {
+	return func(d *Decoder) error {
+		if code < 400 || code > 499 {
+			return fmt.Errorf("the %d status isn't a client error", code)
+		}
+
+		d.status = code
+		return nil
+	}
+}
+
+// WithErrorHandler sets the function that writes the response to the
+// request with an invalid or absent key. The error is the *ParamError.
+func WithErrorHandler(
+	fn func(w http.ResponseWriter, r *http.Request, err error),
+) Option {
+	return func(d *Decoder) error {
+		if fn == nil {
+			return errors.New("nil error handler")
+		}
+
+		d.onError = fn
+		return nil
+	}
+}
+
+// WithPathFunc sets the function that returns the value of the path
+// parameter of the request, it's useful for third-party routers. By
+// default the PathValue method of the request is used (Go 1.22+).
+func WithPathFunc(fn func(r *http.Request, name string) string) Option {
+	return func(d *Decoder) error {
+		if fn == nil {
+			return errors.New("nil path function")
+		}
+
+		d.path = fn
+		return nil
+	}
+}
+
+// Decoder is the middleware that decodes the keys of the request
+// parameters and stores the IDs in the request context.
+//
+// The Decoder is safe for concurrent use by multiple goroutines.
+// It should be created by the NewDecoder function only.
+type Decoder struct {
+	params  []Param
+	status  int
+	onError func(w http.ResponseWriter, r *http.Request, err error)
+	path    func(r *http.Request, name string) string
+}
+
+// NewDecoder returns a new Decoder of the parameters.
+func NewDecoder(params []Param, opts ...Option) (*Decoder, error) {
+	if len(params) == 0 {
+		return &Decoder{}, errors.New("no parameters to decode")
+	}
+
+	for _, p := range params {
+		if p.Name == "" || p.Locksmith == nil {
+			return &Decoder{}, errors.New("the parameter must have " +
+				"the name and Locksmith")
+		}
+
+		if p.Source != Path && p.Source != Query {
+			return &Decoder{}, fmt.Errorf("unknown source %d of the %s "+
+				"parameter", p.Source, p.Name)
+		}
+	}
+
+	d := &Decoder{
+		params: append([]Param(nil), params...),
+		status: http.StatusNotFound,
+		path:   pathValue,
+	}
+
+	for _, opt := range opts {
+		if err := opt(d); err != nil {
+			return &Decoder{}, err
+		}
+	}
+
+	return d, nil
+}
+
+// Handler returns the handler that decodes the parameters of the
+// request and calls the next handler with the IDs in the context.
+// The request with an invalid or absent key gets the error response
+// with the status of the *ParamError.
+func (d *Decoder) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, err := d.Decode(r)
+		if err != nil {
+			if d.onError != nil {
+				d.onError(w, r, err)
+				return
+			}
+
+			status := d.status
+			var pe *ParamError
+			if errors.As(err, &pe) {
+				status = pe.Status
+			}
+
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Decode decodes the parameters of the request and returns the request
+// with the IDs in the context. The error is the *ParamError.
+func (d *Decoder) Decode(r *http.Request) (*http.Request, error) {
+	ids := make(map[string]uint64, len(d.params))
+	if prev, ok := r.Context().Value(idsKey{}).(map[string]uint64); ok {
+		for name, id := range prev {
+			ids[name] = id
+		}
+	}
+
+	for _, p := range d.params {
+		var value string
+		if p.Source == Path {
+			value = d.path(r, p.Name)
+		} else {
+			value = r.URL.Query().Get(p.Name)
+		}
+
+		if value == "" {
+			if p.Optional {
+				continue
+			}
+
+			return r, &ParamError{
+				Param:  p.Name,
+				Status: http.StatusBadRequest,
+			}
+		}
+
+		id, err := p.Locksmith.Unmarshal(value)
+		if err != nil {
+			return r, &ParamError{
+				Param:  p.Name,
+				Key:    value,
+				Status: d.status,
+				Err:    err,
+			}
+		}
+
+		ids[p.Name] = id
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), idsKey{}, ids)), nil
+}
+
+// ID returns the decoded ID of the parameter from the request context,
+// the false result means that the parameter isn't decoded.
+func ID(r *http.Request, name string) (uint64, bool) {
+	return FromContext(r.Context(), name)
+}
+
+// FromContext returns the decoded ID of the parameter from the context.
+func FromContext(ctx context.Context, name string) (uint64, bool) {
+	ids, _ := ctx.Value(idsKey{}).(map[string]uint64)
+	id, ok := ids[name]
+	return id, ok
+}
+
+// The idsKey is the context key of the decoded IDs.
+type idsKey struct{}
+
+// The pathValue returns the value of the path parameter by the
+// PathValue method of the request, which is available since Go 1.22.
+func pathValue(r *http.Request, name string) string {
+	if pv, ok := any(r).(interface{ PathValue(string) string }); ok {
+		return pv.PathValue(name)
+	}
+
+	return ""
+}
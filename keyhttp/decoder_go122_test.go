@@ -0,0 +1,22 @@
+//go:build go1.22
+
+package keyhttp
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// TestDecoderPathValue tests the path parameters of the http.ServeMux.
+func TestDecoderPathValue(t *testing.T) {
+	d, _ := NewDecoder([]Param{PathParam("id", orders)})
+
+	r := httptest.NewRequest("GET", "/orders/1111111B", nil)
+	r.SetPathValue("id", "1111111B")
+
+	w := httptest.NewRecorder()
+	d.Handler(echo("id")).ServeHTTP(w, r)
+	if want := "id=10;"; w.Body.String() != want {
+		t.Errorf("body = %q, want %q", w.Body.String(), want)
+	}
+}
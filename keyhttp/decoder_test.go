@@ -0,0 +1,169 @@
+package keyhttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/goloop/key"
+)
+
+// The orders is the Locksmith of the tests.
+var orders, _ = key.New(key.Base58, 8)
+
+// The echo writes the decoded IDs of the parameters.
+func echo(names ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, name := range names {
+			id, ok := ID(r, name)
+			if !ok {
+				w.Write([]byte(name + "=none;"))
+				continue
+			}
+
+			w.Write([]byte(name + "=" + strconv.FormatUint(id, 10) + ";"))
+		}
+	})
+}
+
+// The pathFunc returns the last segment of the path as the id parameter.
+func pathFunc(r *http.Request, name string) string {
+	if name != "id" {
+		return ""
+	}
+
+	return r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+}
+
+// TestNewDecoder tests the errors of the NewDecoder function.
+func TestNewDecoder(t *testing.T) {
+	tests := []struct {
+		name   string
+		params []Param
+		opts   []Option
+	}{
+		{"No parameters", nil, nil},
+		{"No name", []Param{PathParam("", orders)}, nil},
+		{"No Locksmith", []Param{QueryParam("id", nil)}, nil},
+		{"Unknown source", []Param{{"id", Source(5), orders, false}}, nil},
+		{"Bad status", []Param{PathParam("id", orders)},
+			[]Option{WithStatus(500)}},
+		{"Nil handler", []Param{PathParam("id", orders)},
+			[]Option{WithErrorHandler(nil)}},
+		{"Nil path", []Param{PathParam("id", orders)},
+			[]Option{WithPathFunc(nil)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := NewDecoder(test.params, test.opts...); err == nil {
+				t.Error("NewDecoder() should return an error")
+			}
+		})
+	}
+}
+
+// TestDecoderHandler tests the decoding of the parameters.
+func TestDecoderHandler(t *testing.T) {
+	d, err := NewDecoder([]Param{
+		PathParam("id", orders),
+		{Name: "ref", Source: Query, Locksmith: orders, Optional: true},
+	}, WithPathFunc(pathFunc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := d.Handler(echo("id", "ref"))
+	tests := []struct {
+		url    string
+		status int
+		body   string
+	}{
+		{"/orders/1111111B", 200, "id=10;ref=none;"},
+		{"/orders/1111111B?ref=11111121", 200, "id=10;ref=58;"},
+		{"/orders/1111111I", 404, ""},
+		{"/orders/", 400, ""},
+		{"/orders/1111111B?ref=bad", 404, ""},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", test.url, nil))
+
+		if w.Code != test.status {
+			t.Errorf("%s: status = %d, want %d", test.url, w.Code,
+				test.status)
+		}
+
+		if test.body != "" && w.Body.String() != test.body {
+			t.Errorf("%s: body = %q, want %q", test.url, w.Body.String(),
+				test.body)
+		}
+	}
+}
+
+// TestDecoderStatus tests the configurable error response.
+func TestDecoderStatus(t *testing.T) {
+	d, _ := NewDecoder([]Param{QueryParam("id", orders)},
+		WithStatus(http.StatusBadRequest))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?id=0", nil)
+	d.Handler(echo("id")).ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+
+	var got error
+	d, _ = NewDecoder([]Param{QueryParam("id", orders)},
+		WithErrorHandler(func(w http.ResponseWriter, r *http.Request,
+			err error) {
+			got = err
+			w.WriteHeader(http.StatusTeapot)
+		}))
+
+	w = httptest.NewRecorder()
+	d.Handler(echo("id")).ServeHTTP(w, r)
+	if w.Code != http.StatusTeapot {
+		t.Errorf("status = %d, want 418", w.Code)
+	}
+
+	var pe *ParamError
+	if !errors.As(got, &pe) || pe.Param != "id" || pe.Key != "0" ||
+		pe.Status != http.StatusNotFound || pe.Err == nil {
+		t.Errorf("error = %#v", got)
+	}
+
+	// The absent parameter is the bad request for any status.
+	d, _ = NewDecoder([]Param{QueryParam("id", orders)},
+		WithStatus(http.StatusGone))
+
+	w = httptest.NewRecorder()
+	d.Handler(echo("id")).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	d.Handler(echo("id")).ServeHTTP(w, r)
+	if w.Code != http.StatusGone {
+		t.Errorf("status = %d, want 410", w.Code)
+	}
+}
+
+// TestDecoderNested tests that the nested decoders keep the IDs.
+func TestDecoderNested(t *testing.T) {
+	outer, _ := NewDecoder([]Param{QueryParam("a", orders)})
+	inner, _ := NewDecoder([]Param{QueryParam("b", orders)})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?a=1111111B&b=11111121", nil)
+	outer.Handler(inner.Handler(echo("a", "b"))).ServeHTTP(w, r)
+
+	if want := "a=10;b=58;"; w.Body.String() != want {
+		t.Errorf("body = %q, want %q", w.Body.String(), want)
+	}
+}
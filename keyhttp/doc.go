@@ -0,0 +1,24 @@
+// Package keyhttp provides net/http middleware that decodes the keys of
+// the path and query parameters into numeric IDs via the key.Locksmith,
+// so the handlers get the IDs from the request context and the invalid
+// keys are rejected before the handler. The Rewriter converts the IDs
+// of the JSON bodies into keys and back.
+//
+// Example usage:
+//
+//	orders, _ := key.New(key.Base58, 8)
+//	d, err := keyhttp.NewDecoder([]keyhttp.Param{
+//	    keyhttp.PathParam("id", orders),
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	mux := http.NewServeMux()
+//	mux.Handle("GET /orders/{id}", d.Handler(http.HandlerFunc(
+//	    func(w http.ResponseWriter, r *http.Request) {
+//	        id, _ := keyhttp.ID(r, "id") // decoded uint64 ID
+//	        fmt.Fprintln(w, id)
+//	    },
+//	)))
+package keyhttp
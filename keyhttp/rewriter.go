@@ -0,0 +1,432 @@
+package keyhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/goloop/key"
+)
+
+// Rewriter is the middleware that converts the numeric IDs of the JSON
+// responses into keys and the keys of the JSON requests back into IDs,
+// so the internal services work with IDs while the clients see keys.
+//
+// The fields are selected by the patterns of two kinds:
+//
+//   - the pattern of the field name, like "id" or "*_id", it's matched
+//     against the names of the fields at any depth;
+//   - the JSON path that starts with "$.", like "$.order.customer" or
+//     "$.items.*.id", where the segments are the field names or the
+//     indexes of arrays, and the "*" segment matches any of them.
+//
+// The segments of patterns use the syntax of the path.Match function.
+// If the selected field is an array, its elements are converted. The
+// order of fields and other values are kept as is. The selected values
+// of other JSON types, like the string "request_id" field selected by
+// the "*_id" pattern of the response, are kept as is too, unless the
+// RewriterStrict option is set.
+//
+// The Rewriter is safe for concurrent use by multiple goroutines.
+// It should be created by the NewRewriter function only.
+type Rewriter struct {
+	ls      *key.Locksmith
+	fields  []string   // patterns of the field names
+	paths   [][]string // segments of the JSON paths
+	strict  bool       // true if the values of other types are the error
+	onError func(r *http.Request, err error)
+}
+
+// RewriterOption is the option of the Rewriter.
+type RewriterOption func(*Rewriter) error
+
+// RewriterStrict makes the values of the selected fields of other JSON
+// types the error: the response ID must be a number and the request key
+// must be a string.
+func RewriterStrict() RewriterOption {
+	return func(rw *Rewriter) error {
+		rw.strict = true
+		return nil
+	}
+}
+
+// RewriterErrorHandler sets the function that is called with the error
+// of the encoding of the response, before the response is replaced by
+// the 500 Internal Server Error, for example to log the error.
+func RewriterErrorHandler(fn func(r *http.Request, err error)) RewriterOption {
+	return func(rw *Rewriter) error {
+		if fn == nil {
+			return errors.New("nil error handler")
+		}
+
+		rw.onError = fn
+		return nil
+	}
+}
+
+// NewRewriter returns a new Rewriter of the fields selected by the
+// patterns, the keys are generated by the ls Locksmith.
+//
+// Example usage:
+//
+//	rw, err := keyhttp.NewRewriter(orders, "id", "*_id")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	// {"id":10,"user_id":58} => {"id":"1111111B","user_id":"11111121"}
+//	http.ListenAndServe(":8080", rw.Handler(internal))
+func NewRewriter(ls *key.Locksmith, patterns ...string) (*Rewriter, error) {
+	if ls == nil {
+		return &Rewriter{}, errors.New("nil Locksmith")
+	}
+
+	if len(patterns) == 0 {
+		return &Rewriter{}, errors.New("no field patterns")
+	}
+
+	rw := &Rewriter{ls: ls}
+	for _, p := range patterns {
+		segments := []string{p}
+		if strings.HasPrefix(p, "$.") {
+			segments = strings.Split(p[2:], ".")
+		}
+
+		for _, s := range segments {
+			if _, err := path.Match(s, ""); err != nil || s == "" {
+				return &Rewriter{}, fmt.Errorf("invalid pattern %q", p)
+			}
+		}
+
+		if strings.HasPrefix(p, "$.") {
+			rw.paths = append(rw.paths, segments)
+		} else {
+			rw.fields = append(rw.fields, p)
+		}
+	}
+
+	return rw, nil
+}
+
+// With returns a copy of the Rewriter with the specified options.
+// The original Rewriter isn't changed.
+//
+// Example usage:
+//
+//	rw, _ := keyhttp.NewRewriter(orders, "id", "*_id")
+//	rw, err := rw.With(keyhttp.RewriterErrorHandler(
+//	    func(r *http.Request, err error) {
+//	        log.Printf("%s %s: %v", r.Method, r.URL, err)
+//	    },
+//	))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (rw *Rewriter) With(opts ...RewriterOption) (*Rewriter, error) {
+	clone := *rw
+	for _, opt := range opts {
+		if err := opt(&clone); err != nil {
+			return &Rewriter{}, err
+		}
+	}
+
+	return &clone, nil
+}
+
+// Encode converts the IDs of the selected fields of the JSON document
+// into keys. The null values are kept, other non-numeric values are
+// kept too, or they are the error in the strict mode.
+func (rw *Rewriter) Encode(data []byte) ([]byte, error) {
+	return rw.rewrite(data, func(v json.Token) (json.Token, error) {
+		switch v := v.(type) {
+		case nil:
+			return nil, nil
+		case json.Number:
+			id, err := strconv.ParseUint(string(v), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ID %s", v)
+			}
+
+			return rw.ls.Marshal(id)
+		}
+
+		if !rw.strict {
+			return v, nil
+		}
+
+		return nil, fmt.Errorf("the ID must be a number, got %v", v)
+	})
+}
+
+// Decode converts the keys of the selected fields of the JSON document
+// into IDs. The null values are kept, other non-string values are kept
+// too, or they are the error in the strict mode.
+func (rw *Rewriter) Decode(data []byte) ([]byte, error) {
+	return rw.rewrite(data, func(v json.Token) (json.Token, error) {
+		switch v := v.(type) {
+		case nil:
+			return nil, nil
+		case string:
+			id, err := rw.ls.Unmarshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid key %q: %w", v, err)
+			}
+
+			return json.Number(strconv.FormatUint(id, 10)), nil
+		}
+
+		if !rw.strict {
+			return v, nil
+		}
+
+		return nil, fmt.Errorf("the key must be a string, got %v", v)
+	})
+}
+
+// Handler returns the handler that decodes the keys of the JSON request
+// body, calls the next handler and encodes the IDs of its JSON response.
+//
+// The request with an invalid key gets the 400 Bad Request response,
+// and the response with an invalid ID is replaced by the 500 Internal
+// Server Error, the error is passed to the RewriterErrorHandler. The
+// bodies of other content types aren't changed.
+func (rw *Rewriter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body != nil && isJSON(r.Header) {
+			data, err := io.ReadAll(r.Body)
+			r.Body.Close()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if len(bytes.TrimSpace(data)) > 0 {
+				data, err = rw.Decode(data)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(data))
+			r.ContentLength = int64(len(data))
+			r.Header.Del("Content-Length")
+		}
+
+		buf := &buffer{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(buf, r)
+
+		body := buf.body.Bytes()
+		if isJSON(buf.header) && len(bytes.TrimSpace(body)) > 0 {
+			var err error
+			body, err = rw.Encode(body)
+			if err != nil {
+				if rw.onError != nil {
+					rw.onError(r, err)
+				}
+
+				http.Error(w, http.StatusText(http.StatusInternalServerError),
+					http.StatusInternalServerError)
+				return
+			}
+
+			buf.header.Del("Content-Length")
+		}
+
+		for name, values := range buf.header {
+			w.Header()[name] = values
+		}
+
+		w.WriteHeader(buf.status)
+		w.Write(body)
+	})
+}
+
+// The rewrite copies the JSON document and replaces the values
+// of the selected fields by the convert function.
+func (rw *Rewriter) rewrite(
+	data []byte,
+	convert func(json.Token) (json.Token, error),
+) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var out bytes.Buffer
+	if err := rw.value(dec, &out, nil, "", false, convert); err != nil {
+		return nil, err
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid JSON: data after the value")
+	}
+
+	return out.Bytes(), nil
+}
+
+// The value copies the next value of the decoder into the buffer.
+// The trail is the path of the value, the name is the name of the
+// nearest field, the selected is true if the value is the element
+// of the selected array.
+func (rw *Rewriter) value(
+	dec *json.Decoder,
+	out *bytes.Buffer,
+	trail []string,
+	name string,
+	selected bool,
+	convert func(json.Token) (json.Token, error),
+) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	selected = selected || (trail != nil && rw.match(trail, name))
+
+	switch tok {
+	case json.Delim('{'):
+		out.WriteByte('{')
+		for i := 0; dec.More(); i++ {
+			tok, err := dec.Token()
+			if err != nil {
+				return fmt.Errorf("invalid JSON: %w", err)
+			}
+
+			field := tok.(string)
+			if i > 0 {
+				out.WriteByte(',')
+			}
+
+			writeToken(out, field)
+			out.WriteByte(':')
+
+			err = rw.value(dec, out, append(trail, field), field, false,
+				convert)
+			if err != nil {
+				return err
+			}
+		}
+
+		if _, err := dec.Token(); err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+
+		out.WriteByte('}')
+		return nil
+	case json.Delim('['):
+		out.WriteByte('[')
+		for i := 0; dec.More(); i++ {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+
+			err := rw.value(dec, out, append(trail, strconv.Itoa(i)),
+				name, selected, convert)
+			if err != nil {
+				return err
+			}
+		}
+
+		if _, err := dec.Token(); err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+
+		out.WriteByte(']')
+		return nil
+	}
+
+	if selected {
+		if tok, err = convert(tok); err != nil {
+			return fmt.Errorf("the %s field: %w", name, err)
+		}
+	}
+
+	writeToken(out, tok)
+	return nil
+}
+
+// The match returns true if the field with the trail path
+// and the name is selected by the patterns.
+func (rw *Rewriter) match(trail []string, name string) bool {
+	for _, p := range rw.fields {
+		if ok, _ := path.Match(p, name); ok && name != "" {
+			return true
+		}
+	}
+
+	for _, segments := range rw.paths {
+		if len(segments) != len(trail) {
+			continue
+		}
+
+		ok := true
+		for i, s := range segments {
+			if ok, _ = path.Match(s, trail[i]); !ok {
+				break
+			}
+		}
+
+		if ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// The writeToken writes the scalar JSON token into the buffer.
+func writeToken(out *bytes.Buffer, tok json.Token) {
+	if n, ok := tok.(json.Number); ok {
+		out.WriteString(string(n))
+		return
+	}
+
+	// The encoder adds the new line after the value.
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	enc.Encode(tok)
+	out.Truncate(out.Len() - 1)
+}
+
+// The isJSON returns true if the content type of the header is JSON.
+func isJSON(h http.Header) bool {
+	ct := strings.ToLower(h.Get("Content-Type"))
+	if i := strings.IndexByte(ct, ';'); i >= 0 {
+		ct = ct[:i]
+	}
+
+	ct = strings.TrimSpace(ct)
+	return ct == "application/json" || strings.HasSuffix(ct, "+json")
+}
+
+// The buffer is the http.ResponseWriter that keeps the response
+// in the memory.
+type buffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+	wrote  bool
+}
+
+// Header returns the header of the response.
+func (b *buffer) Header() http.Header {
+	return b.header
+}
+
+// WriteHeader sets the status code of the response.
+func (b *buffer) WriteHeader(status int) {
+	if !b.wrote {
+		b.status, b.wrote = status, true
+	}
+}
+
+// Write writes the data into the body of the response.
+func (b *buffer) Write(data []byte) (int, error) {
+	b.wrote = true
+	return b.body.Write(data)
+}
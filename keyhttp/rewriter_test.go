@@ -0,0 +1,212 @@
+package keyhttp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestNewRewriter tests the errors of the NewRewriter function.
+func TestNewRewriter(t *testing.T) {
+	if _, err := NewRewriter(nil, "id"); err == nil {
+		t.Error("NewRewriter(nil) should return an error")
+	}
+
+	if _, err := NewRewriter(orders); err == nil {
+		t.Error("NewRewriter() without patterns should return an error")
+	}
+
+	for _, p := range []string{"[", "$.a..b", "$.items.["} {
+		if _, err := NewRewriter(orders, p); err == nil {
+			t.Errorf("NewRewriter(%q) should return an error", p)
+		}
+	}
+}
+
+// TestRewriterEncode tests the Encode and Decode methods.
+func TestRewriterEncode(t *testing.T) {
+	rw, err := NewRewriter(orders, "id", "*_ids", "$.order.customer",
+		"$.items.*.sku")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		ids  string
+		keys string
+	}{
+		{
+			"Fields",
+			`{"name":"<b>","id":10,"user_id":58,"count":3}`,
+			`{"name":"<b>","id":"1111111B","user_id":58,"count":3}`,
+		},
+		{
+			"Nested and arrays",
+			`{"order":{"id":null,"customer":58},"tag_ids":[10,58]}`,
+			`{"order":{"id":null,"customer":"11111121"},` +
+				`"tag_ids":["1111111B","11111121"]}`,
+		},
+		{
+			"JSON path",
+			`{"items":[{"sku":10,"qty":1},{"sku":58,"qty":2}],"sku":5}`,
+			`{"items":[{"sku":"1111111B","qty":1},` +
+				`{"sku":"11111121","qty":2}],"sku":5}`,
+		},
+		{
+			"Root array",
+			`[{"id":10},{"id":58}]`,
+			`[{"id":"1111111B"},{"id":"11111121"}]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := rw.Encode([]byte(test.ids))
+			if err != nil || string(got) != test.keys {
+				t.Errorf("Encode() = %s, %v, want %s", got, err, test.keys)
+			}
+
+			got, err = rw.Decode([]byte(test.keys))
+			if err != nil || string(got) != test.ids {
+				t.Errorf("Decode() = %s, %v, want %s", got, err, test.ids)
+			}
+		})
+	}
+
+	for _, data := range []string{`{"id":-1}`, `{"id":1.5}`, `{"id":1`,
+		`{"id":1} {}`} {
+		if _, err := rw.Encode([]byte(data)); err == nil {
+			t.Errorf("Encode(%s) should return an error", data)
+		}
+	}
+
+	if _, err := rw.Decode([]byte(`{"id":"1111111I"}`)); err == nil {
+		t.Errorf("Decode() should return an error")
+	}
+}
+
+// TestRewriterStrict tests the values of other JSON types.
+func TestRewriterStrict(t *testing.T) {
+	rw, _ := NewRewriter(orders, "id", "*_id")
+
+	data := `{"id":10,"request_id":"9f1c-abc","flag_id":true}`
+	want := `{"id":"1111111B","request_id":"9f1c-abc","flag_id":true}`
+	got, err := rw.Encode([]byte(data))
+	if err != nil || string(got) != want {
+		t.Errorf("Encode() = %s, %v, want %s", got, err, want)
+	}
+
+	data = `{"id":"1111111B","count_id":5}`
+	want = `{"id":10,"count_id":5}`
+	got, err = rw.Decode([]byte(data))
+	if err != nil || string(got) != want {
+		t.Errorf("Decode() = %s, %v, want %s", got, err, want)
+	}
+
+	strict, err := rw.With(RewriterStrict())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := strict.Encode([]byte(`{"id":"x"}`)); err == nil {
+		t.Error("Encode() should return an error in the strict mode")
+	}
+
+	if _, err := strict.Decode([]byte(`{"id":10}`)); err == nil {
+		t.Error("Decode() should return an error in the strict mode")
+	}
+
+	if _, err := rw.With(RewriterErrorHandler(nil)); err == nil {
+		t.Error("With(RewriterErrorHandler(nil)) should return an error")
+	}
+}
+
+// TestRewriterHandler tests the middleware.
+func TestRewriterHandler(t *testing.T) {
+	rw, _ := NewRewriter(orders, "id")
+
+	handler := rw.Handler(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			if r.URL.Path == "/text" {
+				w.Header().Set("Content-Type", "text/plain")
+			} else {
+				w.Header().Set("Content-Type", "application/json")
+			}
+
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		},
+	))
+
+	tests := []struct {
+		name   string
+		url    string
+		ctype  string
+		body   string
+		status int
+		want   string
+	}{
+		{"Round trip", "/", "application/json", `{"id":"1111111B"}`,
+			http.StatusCreated, `{"id":"1111111B"}`},
+		{"Invalid key", "/", "application/json; charset=utf-8",
+			`{"id":"bad"}`, http.StatusBadRequest, ""},
+		{"Invalid ID", "/", "text/plain", `{"id":-1}`,
+			http.StatusInternalServerError, ""},
+		{"Other type", "/text", "text/plain", `{"id":10}`,
+			http.StatusCreated, `{"id":10}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", test.url,
+				strings.NewReader(test.body))
+			r.Header.Set("Content-Type", test.ctype)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != test.status {
+				t.Errorf("status = %d, want %d", w.Code, test.status)
+			}
+
+			if test.want != "" && w.Body.String() != test.want {
+				t.Errorf("body = %s, want %s", w.Body.String(), test.want)
+			}
+		})
+	}
+}
+
+// TestRewriterHandlerStringID tests the response with the string field
+// selected by the name pattern, and the error handler.
+func TestRewriterHandlerStringID(t *testing.T) {
+	body := `{"id":10,"request_id":"9f1c-abc"}`
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	})
+
+	var got error
+	rw, _ := NewRewriter(orders, "id", "*_id")
+	rw, _ = rw.With(RewriterErrorHandler(func(r *http.Request, err error) {
+		got = err
+	}))
+
+	w := httptest.NewRecorder()
+	rw.Handler(next).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	want := `{"id":"1111111B","request_id":"9f1c-abc"}`
+	if w.Code != http.StatusOK || w.Body.String() != want || got != nil {
+		t.Errorf("response = %d %s (%v), want 200 %s", w.Code,
+			w.Body.String(), got, want)
+	}
+
+	strict, _ := rw.With(RewriterStrict())
+	w = httptest.NewRecorder()
+	strict.Handler(next).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusInternalServerError || got == nil {
+		t.Errorf("response = %d (%v), want 500 with the error", w.Code, got)
+	}
+}
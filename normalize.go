@@ -0,0 +1,233 @@
+package key
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// The symbol is the character of the key entered by the user
+// and its position in the entered string, in runes.
+type symbol struct {
+	char     rune
+	position int // -1 for the added padding characters
+}
+
+// Normalize returns the canonical form of the key entered by the user,
+// i.e. the key that the Marshal method returns for the same ID.
+//
+// The method cleans the key before the check: it trims the spaces,
+// removes the spaces, dashes and group separators that aren't set in
+// the alphabet, folds the case of the characters (and the prefix) that
+// are set in the alphabet in other case only, and fixes the leading
+// padding characters - adds them to the short fixed size key and removes
+// them from the dynamic one. The padding isn't fixed for the keys with
+// the blocklist. Then the key is checked like by the Unmarshal method:
+// its length, characters, check character and the range of the ID.
+//
+// The key of the Locksmith with the blocklist that has the wrong tweak
+// character (i.e. the blocked or non-canonical key) can't be fixed, the
+// error wraps the ErrNonCanonical then.
+//
+// Example usage:
+//
+//	ls, _ := New(Crockford32, 8)
+//	key, err := ls.Normalize(" 4dm-3k ")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(key) // Output: "0004DM3K"
+func (ls *Locksmith) Normalize(key string) (string, error) {
+	symbols, err := ls.clean(key)
+	if err != nil {
+		return "", err
+	}
+
+	// The check character is computed over the whole alphabet.
+	if ls.pattern == nil {
+		for i, s := range symbols {
+			char, ok := fold(s.char, ls.indexOf)
+			if !ok {
+				return "", &InvalidCharError{
+					Rune:     s.char,
+					Position: s.position,
+				}
+			}
+
+			symbols[i].char = char
+		}
+	}
+
+	chars := make([]rune, len(symbols))
+	for i, s := range symbols {
+		chars[i] = s.char
+	}
+
+	chars, err = ls.verify(chars)
+	if err != nil {
+		return "", err
+	}
+	symbols = symbols[:len(chars)]
+
+	if ls.blocklist == nil {
+		symbols = ls.pad(symbols)
+	}
+
+	l := uint64(len(symbols))
+	if ls.blocklist != nil {
+		if l < 2 {
+			return "", errorf(ErrInvalidLength, "the key is too short")
+		}
+
+		l-- // the tweak character isn't included in the size
+	}
+
+	if ls.size > 0 && l != ls.size {
+		return "", errorf(ErrInvalidLength, "invalid key length, "+
+			"must be %d char(s) but %d char(s)", ls.size, l)
+	}
+
+	value := make([]int, len(symbols))
+	for i, s := range symbols {
+		_, indexOf := ls.digit(i)
+		char, ok := fold(s.char, indexOf)
+		if !ok {
+			return "", &InvalidCharError{
+				Rune:     s.char,
+				Position: s.position,
+			}
+		}
+
+		value[i] = indexOf[char]
+	}
+
+	result := ls.format(value)
+	if ls.blocklist != nil {
+		_, err := ls.uncensor(result, value)
+		if errors.Is(err, ErrInvalidChar) {
+			// The key has the wrong tweak character, its ID has
+			// other canonical key.
+			return "", errorf(ErrNonCanonical, "the %q key is blocked "+
+				"or isn't canonical", result)
+		}
+
+		if err != nil {
+			return "", err
+		}
+	} else if _, ok := ls.join(value); !ok {
+		return "", errorf(ErrOverflow, "the %q key is out of range", result)
+	}
+
+	return result, nil
+}
+
+// Validate checks the key without the decoding into the ID. It returns
+// nil if the key is the canonical key of some ID, i.e. the Unmarshal
+// method accepts it and the Marshal method returns it for its ID.
+//
+// The error wraps the ErrNonCanonical if the key is valid but isn't
+// canonical (the Normalize method returns its canonical form, except
+// the keys with the wrong tweak character of the blocklist), or the
+// same error as the Normalize method.
+//
+// Example usage:
+//
+//	ls, _ := New("abc", 3)
+//	if err := ls.Validate("bab"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (ls *Locksmith) Validate(key string) error {
+	canonical, err := ls.Normalize(key)
+	if err != nil {
+		return err
+	}
+
+	if canonical != key {
+		return errorf(ErrNonCanonical, "the %q key isn't canonical, "+
+			"must be %q", key, canonical)
+	}
+
+	return nil
+}
+
+// The clean removes the spaces, prefix and separators of the key.
+func (ls *Locksmith) clean(key string) ([]symbol, error) {
+	runes := []rune(key)
+	start, end := 0, len(runes)
+	for start < end && unicode.IsSpace(runes[start]) {
+		start++
+	}
+
+	for end > start && unicode.IsSpace(runes[end-1]) {
+		end--
+	}
+
+	if ls.prefix != "" {
+		n := len([]rune(ls.prefix))
+		if end-start < n ||
+			!strings.EqualFold(string(runes[start:start+n]), ls.prefix) {
+			return nil, errorf(ErrInvalidChar,
+				"the key must start with the %q prefix", ls.prefix)
+		}
+
+		start += n
+	}
+
+	result := make([]symbol, 0, end-start)
+	for i := start; i < end; i++ {
+		char := runes[i]
+		if _, ok := ls.indexOf[char]; !ok && (unicode.IsSpace(char) ||
+			char == '-' || (ls.group > 0 && char == ls.separator)) {
+			continue
+		}
+
+		result = append(result, symbol{char: char, position: i})
+	}
+
+	if len(result) == 0 {
+		return nil, errorf(ErrInvalidLength, "blank key")
+	}
+
+	return result, nil
+}
+
+// The pad adds the leading padding characters to the short fixed size
+// key, and removes them from the dynamic key or the long fixed size key.
+func (ls *Locksmith) pad(symbols []symbol) []symbol {
+	size := int(ls.size)
+	if ls.pattern == nil {
+		zero := ls.alphabet[0]
+		for len(symbols) > 1 && len(symbols) > size &&
+			symbols[0].char == zero {
+			symbols = symbols[1:]
+		}
+	}
+
+	if len(symbols) >= size {
+		return symbols
+	}
+
+	result := make([]symbol, size-len(symbols), size)
+	for i := range result {
+		chars, _ := ls.digit(i)
+		result[i] = symbol{char: chars[0], position: -1}
+	}
+
+	return append(result, symbols...)
+}
+
+// The fold returns the char or the char in other case that is set
+// in the alphabet, it returns false if there is no such char.
+func fold(char rune, indexOf map[rune]int) (rune, bool) {
+	if _, ok := indexOf[char]; ok {
+		return char, true
+	}
+
+	for f := unicode.SimpleFold(char); f != char; f = unicode.SimpleFold(f) {
+		if _, ok := indexOf[f]; ok {
+			return f, true
+		}
+	}
+
+	return char, false
+}
@@ -0,0 +1,159 @@
+package key
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestNormalize tests Normalize method.
+func TestNormalize(t *testing.T) {
+	crockford, _ := New(Crockford32, 8)
+	dynamic, _ := New("abc")
+	grouped, _ := New(Base58, 8)
+	grouped, _ = grouped.With(WithGroups(4, '-'), WithPrefix("ord_"))
+	checked, _ := New("0123456789", 6)
+	checked, _ = checked.With(WithChecksum())
+	proquint, _ := NewProquint(2)
+	mixed, _ := New("aA", 3)
+
+	tests := []struct {
+		name string
+		ls   *Locksmith
+		key  string
+		want string
+	}{
+		{"canonical", crockford, "0004DM3K", "0004DM3K"},
+		{"case and spaces", crockford, " 4dm 3k\t", "0004DM3K"},
+		{"dashes", crockford, "0004-dm3k", "0004DM3K"},
+		{"long padding", crockford, "000004DM3K", "0004DM3K"},
+		{"dynamic padding", dynamic, "aabab", "bab"},
+		{"dynamic zero", dynamic, "aaa", "a"},
+		{"groups", grouped, "ord_11111-11B", "ord_1111-111B"},
+		{"no groups", grouped, "ORD_B", "ord_1111-111B"},
+		{"checksum", checked, "1234566", "1234566"},
+		{"short checksum", checked, "12-3455", "0123455"},
+		{"pattern", proquint, "LUSAB BABAD", "lusab-babad"},
+		{"pattern padding", proquint, "babad", "babab-babad"},
+		{"both cases", mixed, "Aa", "aAa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.ls.Normalize(tt.key)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("expected %q but %q", tt.want, got)
+			}
+
+			if _, err := tt.ls.Unmarshal(got); err != nil {
+				t.Errorf("the normalized key isn't valid: %v", err)
+			}
+		})
+	}
+}
+
+// TestNormalizeErrors tests Normalize method with the invalid keys.
+func TestNormalizeErrors(t *testing.T) {
+	ls, _ := New("abc", 3)
+	dynamic, _ := New("abc")
+	prefixed, _ := ls.With(WithPrefix("k_"))
+	checked, _ := New("0123456789", 6)
+	checked, _ = checked.With(WithChecksum())
+	blocked, _ := New("abc", 3)
+	blocked, _ = blocked.With(WithBlocklist("cab"))
+
+	tests := []struct {
+		name string
+		ls   *Locksmith
+		key  string
+		want error
+	}{
+		{"blank", ls, "  ", ErrInvalidLength},
+		{"long", ls, "babab", ErrInvalidLength},
+		{"unknown char", ls, "ab!", ErrInvalidChar},
+		{"no prefix", prefixed, "abc", ErrInvalidChar},
+		{"check char", checked, "1234567", ErrInvalidChar},
+		{"overflow", dynamic, "ccccccccccccccccccccccccccccccccccccccccc",
+			ErrOverflow},
+		{"blocked", blocked, "a", ErrInvalidLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.ls.Normalize(tt.key); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v but %v", tt.want, err)
+			}
+		})
+	}
+
+	var ice *InvalidCharError
+	if _, err := ls.Normalize(" a-b!"); !errors.As(err, &ice) ||
+		ice.Rune != '!' || ice.Position != 4 {
+		t.Errorf("expected '!' at 4 but %v", err)
+	}
+}
+
+// TestValidate tests Validate method.
+func TestValidate(t *testing.T) {
+	ls, _ := New(Crockford32, 8)
+	blocked, _ := New("abc", 3)
+	blocked, _ = blocked.With(WithBlocklist("cab"))
+
+	for id := uint64(0); id < 100; id++ {
+		key, _ := ls.Marshal(id * 7919)
+		if err := ls.Validate(key); err != nil {
+			t.Errorf("unexpected error for %q: %v", key, err)
+		}
+	}
+
+	for _, key := range []string{"0004dm3k", "4DM3K", "0004-DM3K"} {
+		if err := ls.Validate(key); !errors.Is(err, ErrNonCanonical) {
+			t.Errorf("expected ErrNonCanonical for %q but %v", key, err)
+		}
+	}
+
+	if err := ls.Validate("0004DM3U"); !errors.Is(err, ErrInvalidChar) {
+		t.Errorf("expected ErrInvalidChar but %v", err)
+	}
+
+	for id := uint64(0); id < 27; id++ {
+		key, err := blocked.Marshal(id)
+		if err != nil {
+			continue
+		}
+
+		if err := blocked.Validate(key); err != nil {
+			t.Errorf("unexpected error for %q: %v", key, err)
+		}
+	}
+}
+
+// TestValidateBlocklist tests Validate and Normalize methods with
+// the blocklist.
+func TestValidateBlocklist(t *testing.T) {
+	ls, _ := New("abc", 3)
+	ls, _ = ls.With(WithBlocklist("cab"))
+
+	key, _ := ls.Marshal(1)
+	if got, err := ls.Normalize(" " + key + " "); err != nil || got != key {
+		t.Errorf("expected %q but %q (%v)", key, got, err)
+	}
+
+	// The valid key with the wrong tweak character.
+	for _, key := range []string{"baab", "BAAB"} {
+		if err := ls.Validate(key); !errors.Is(err, ErrNonCanonical) {
+			t.Errorf("expected ErrNonCanonical for %q but %v", key, err)
+		}
+
+		if _, err := ls.Normalize(key); !errors.Is(err, ErrNonCanonical) {
+			t.Errorf("expected ErrNonCanonical for %q but %v", key, err)
+		}
+	}
+
+	if err := ls.Validate("aa!a"); !errors.Is(err, ErrInvalidChar) {
+		t.Errorf("expected ErrInvalidChar but %v", err)
+	}
+}
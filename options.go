@@ -0,0 +1,99 @@
+package key
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Option is a function that configures optional behavior
+// of the Locksmith object.
+type Option func(*Locksmith) error
+
+// With returns a copy of the Locksmith with the specified options.
+// The original Locksmith object isn't changed.
+//
+// Example usage:
+//
+//	ls, _ := New("abcdefghijklmnopqrstuvwxyz", 8)
+//	ls, err := ls.With(WithGroups(4, '-'))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	key, _ := ls.Marshal(10)
+//	fmt.Println(key) // Output: "aaaa-aaak"
+func (ls *Locksmith) With(opts ...Option) (*Locksmith, error) {
+	clone := *ls
+	for _, opt := range opts {
+		if err := opt(&clone); err != nil {
+			return &Locksmith{}, err
+		}
+	}
+
+	return &clone, nil
+}
+
+// WithGroups splits the key into groups of the specified size,
+// separated by the sep character, like "abcd-efgh-ij". The separator
+// mustn't be a character of the alphabet.
+func WithGroups(size int, sep rune) Option {
+	return func(ls *Locksmith) error {
+		if size < 1 {
+			return errors.New("incorrect group size")
+		}
+
+		if ls.contains(sep) {
+			return fmt.Errorf("the %c separator is set in the alphabet", sep)
+		}
+
+		ls.group, ls.separator = size, sep
+		return nil
+	}
+}
+
+// WithChecksum appends one check character to the keys.
+//
+// The check character is calculated using the Luhn mod N algorithm,
+// where N is the size of the alphabet, it catches any single mistyped
+// character and most of the swaps of the adjacent characters. The check
+// character isn't included in the size of the key.
+//
+// The option isn't supported for the patterned keys, because their
+// positions have different alphabets.
+func WithChecksum() Option {
+	return func(ls *Locksmith) error {
+		if ls.pattern != nil {
+			return errors.New("checksum isn't supported for patterned keys")
+		}
+
+		ls.checksum = true
+		return nil
+	}
+}
+
+// WithPrefix adds the constant prefix to the keys, like "ord_" for
+// the keys of orders. The Unmarshal method requires the prefix, so the
+// keys of the different entities can't be confused, and the Registry
+// detects the entity of the key by its prefix.
+func WithPrefix(prefix string) Option {
+	return func(ls *Locksmith) error {
+		if prefix == "" {
+			return errors.New("blank prefix")
+		}
+
+		ls.prefix = prefix
+		return nil
+	}
+}
+
+// Prefix returns the prefix of the keys, or the empty string
+// if it isn't set.
+func (ls *Locksmith) Prefix() string {
+	return ls.prefix
+}
+
+// The contains returns true if the char is set in the alphabet.
+func (ls *Locksmith) contains(char rune) bool {
+	_, ok := ls.indexOf[char]
+	return ok
+}
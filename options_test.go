@@ -0,0 +1,206 @@
+package key
+
+import "testing"
+
+// TestWith tests With method.
+func TestWith(t *testing.T) {
+	ls, err := New("abcdefghijklmnopqrstuvwxyz", 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grouped, err := ls.With(WithGroups(4, '-'))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The original object isn't changed.
+	if key, _ := ls.Marshal(10); key != "aaaaaaak" {
+		t.Errorf("expected %q but %q", "aaaaaaak", key)
+	}
+
+	if key, _ := grouped.Marshal(10); key != "aaaa-aaak" {
+		t.Errorf("expected %q but %q", "aaaa-aaak", key)
+	}
+}
+
+// TestWithGroups tests WithGroups option.
+func TestWithGroups(t *testing.T) {
+	ls, _ := New("abc")
+	if _, err := ls.With(WithGroups(0, '-')); err == nil {
+		t.Error("expected an error for the zero group size")
+	}
+
+	if _, err := ls.With(WithGroups(3, 'a')); err == nil {
+		t.Error("expected an error for the separator from the alphabet")
+	}
+
+	ls, _ = ls.With(WithGroups(3, '-'))
+	tests := []struct {
+		id  uint64
+		key string
+	}{
+		{1, "b"},
+		{10, "bab"},
+		{100, "bac-ab"},
+		{10000000, "caa-cbb-aab-bac-bab"},
+	}
+
+	for _, test := range tests {
+		key, err := ls.Marshal(test.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if key != test.key {
+			t.Errorf("expected %q but %q", test.key, key)
+		}
+
+		id, err := ls.Unmarshal(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id != test.id {
+			t.Errorf("expected %d but %d", test.id, id)
+		}
+	}
+
+	for _, key := range []string{"bacab", "ba-cab", "bac-", "bac+ab"} {
+		if _, err := ls.Unmarshal(key); err == nil {
+			t.Errorf("%s: expected an error", key)
+		}
+	}
+}
+
+// TestWithChecksum tests WithChecksum option.
+func TestWithChecksum(t *testing.T) {
+	proquint, _ := NewProquint(1)
+	if _, err := proquint.With(WithChecksum()); err == nil {
+		t.Error("expected an error for the patterned keys")
+	}
+
+	// The Luhn mod 10 is the classic Luhn algorithm.
+	ls, _ := New("0123456789")
+	ls, _ = ls.With(WithChecksum())
+	key, err := ls.Marshal(7992739871)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key != "79927398713" {
+		t.Errorf("expected %q but %q", "79927398713", key)
+	}
+
+	if id, err := ls.Unmarshal(key); err != nil || id != 7992739871 {
+		t.Errorf("expected 7992739871 but %d, %v", id, err)
+	}
+
+	// Any single mistyped character is detected.
+	for i := range key {
+		for c := '0'; c <= '9'; c++ {
+			typo := key[:i] + string(c) + key[i+1:]
+			if typo == key {
+				continue
+			}
+
+			if _, err := ls.Unmarshal(typo); err == nil {
+				t.Errorf("%s: expected an error", typo)
+			}
+		}
+	}
+
+	for _, key := range []string{"", "7", "7992739871a"} {
+		if _, err := ls.Unmarshal(key); err == nil {
+			t.Errorf("%q: expected an error", key)
+		}
+	}
+
+	// The check character is grouped and isn't included in the size.
+	ls, _ = New("abc", 3)
+	ls, _ = ls.With(WithChecksum(), WithGroups(2, '-'))
+	for id := uint64(0); id < ls.Total(); id++ {
+		key, err := ls.Marshal(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(key) != 5 {
+			t.Errorf("%s: expected 5 chars", key)
+		}
+
+		if got, err := ls.Unmarshal(key); err != nil || got != id {
+			t.Errorf("%s: expected %d but %d, %v", key, id, got, err)
+		}
+	}
+
+	// The UUID keys have the check character too.
+	ls, _ = New(Base62)
+	ls, _ = ls.With(WithChecksum())
+	uuid := parseUUID(t, "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	key, _ = ls.MarshalUUID(uuid)
+	if len(key) != 23 {
+		t.Errorf("%s: expected 23 chars", key)
+	}
+
+	if got, err := ls.UnmarshalUUID(key); err != nil || got != uuid {
+		t.Errorf("%s: expected %x but %x, %v", key, uuid, got, err)
+	}
+
+	if _, err := ls.UnmarshalUUID(key[:22]); err == nil {
+		t.Errorf("%s: expected an error", key[:22])
+	}
+}
+
+// TestWithPrefix tests WithPrefix option.
+func TestWithPrefix(t *testing.T) {
+	ls, _ := New("abc", 3)
+	if _, err := ls.With(WithPrefix("")); err == nil {
+		t.Error("expected an error for the blank prefix")
+	}
+
+	ls, _ = ls.With(WithPrefix("ab_"), WithGroups(2, '-'))
+	if ls.Prefix() != "ab_" {
+		t.Errorf("expected %q but %q", "ab_", ls.Prefix())
+	}
+
+	key, _ := ls.Marshal(10)
+	if key != "ab_ba-b" {
+		t.Errorf("expected %q but %q", "ab_ba-b", key)
+	}
+
+	if id, err := ls.Unmarshal(key); err != nil || id != 10 {
+		t.Errorf("expected 10 but %d, %v", id, err)
+	}
+
+	for _, key := range []string{"ba-b", "ab-ba-b", "ab_"} {
+		if _, err := ls.Unmarshal(key); err == nil {
+			t.Errorf("%s: expected an error", key)
+		}
+	}
+
+	// The prefix isn't matched by the blocklist.
+	ls, _ = New("abc")
+	ls, _ = ls.With(WithPrefix("bad_"), WithBlocklist("bad"))
+	key, err := ls.Marshal(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id, err := ls.Unmarshal(key); err != nil || id != 1 {
+		t.Errorf("expected 1 but %d, %v", id, err)
+	}
+
+	// The UUID keys have the prefix too.
+	ls, _ = New(Base62)
+	ls, _ = ls.With(WithPrefix("u_"))
+	uuid := parseUUID(t, "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	key, _ = ls.MarshalUUID(uuid)
+	if key != "u_7RKE2sawAICsEsyZKHWW6r" {
+		t.Errorf("expected %q but %q", "u_7RKE2sawAICsEsyZKHWW6r", key)
+	}
+
+	if got, err := ls.UnmarshalUUID(key); err != nil || got != uuid {
+		t.Errorf("%s: expected %x but %x, %v", key, uuid, got, err)
+	}
+}
@@ -0,0 +1,238 @@
+package key
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"sync"
+)
+
+// PartitionMode defines how the key space is split into shards.
+type PartitionMode int
+
+const (
+	// Contiguous splits the key space into consecutive ranges of IDs,
+	// the sizes of the ranges differ by one at most.
+	Contiguous PartitionMode = iota
+
+	// Interleaved assigns the IDs to shards in turn, i.e. the i shard
+	// of n gets the i, i+n, i+2n, ... IDs.
+	Interleaved
+)
+
+// Partition splits the key space [0, Total) of the Locksmith into
+// disjoint shards, so several workers can allocate keys independently.
+//
+// The Partition is safe for concurrent use by multiple goroutines.
+// It should be created by the Partition method of the Locksmith only.
+type Partition struct {
+	ls     *Locksmith
+	mode   PartitionMode
+	shards uint64 // number of shards
+	size   uint64 // number of IDs in the smaller shards
+	rest   uint64 // number of shards with one more ID
+}
+
+// Partition returns a new partition of the key space into n shards.
+//
+// Example usage:
+//
+//	ls, _ := New(Base58, 8)
+//	p, err := ls.Partition(16, Contiguous)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	shard, _ := p.Shard(worker) // the worker is 0..15
+//	key, _ := shard.Next()
+//	index, _ := p.ShardOf(key) // equal to worker
+func (ls *Locksmith) Partition(n int, mode PartitionMode) (*Partition, error) {
+	if mode != Contiguous && mode != Interleaved {
+		return &Partition{}, fmt.Errorf("unknown partition mode %d", mode)
+	}
+
+	if n < 1 {
+		return &Partition{}, fmt.Errorf("incorrect number of shards %d", n)
+	}
+
+	p := &Partition{ls: ls, mode: mode, shards: uint64(n)}
+	if n == 1 {
+		return p, nil // the size isn't used
+	}
+
+	// The key space has 2^64 IDs if the total is MaxUint64.
+	var hi, lo uint64 = 0, ls.total
+	if ls.total == math.MaxUint64 {
+		hi, lo = 1, 0
+	}
+
+	p.size, p.rest = bits.Div64(hi, lo, p.shards)
+	if p.size == 0 {
+		return &Partition{}, fmt.Errorf("can't split %d keys into %d "+
+			"shards", ls.total, n)
+	}
+
+	return p, nil
+}
+
+// Shards returns the number of shards.
+func (p *Partition) Shards() int {
+	return int(p.shards)
+}
+
+// Shard returns a new allocator of the keys of the i shard,
+// the index is in the range [0, Shards).
+func (p *Partition) Shard(i int) (*Shard, error) {
+	if i < 0 || uint64(i) >= p.shards {
+		return &Shard{}, fmt.Errorf("the %d shard is out of range", i)
+	}
+
+	s := &Shard{ls: p.ls, index: i, step: 1}
+	if p.shards == 1 {
+		s.last = p.ls.last()
+		return s, nil
+	}
+
+	index := uint64(i)
+	s.last = p.size - 1
+	if index < p.rest {
+		s.last++
+	}
+
+	if p.mode == Interleaved {
+		s.first, s.step = index, p.shards
+	} else {
+		s.first = index * p.size
+		if index < p.rest {
+			s.first += index
+		} else {
+			s.first += p.rest
+		}
+	}
+
+	return s, nil
+}
+
+// ShardOf returns the index of the shard that contains the key.
+func (p *Partition) ShardOf(key string) (int, error) {
+	id, err := p.ls.Unmarshal(key)
+	if err != nil {
+		return 0, err
+	}
+
+	return p.ShardOfID(id), nil
+}
+
+// ShardOfID returns the index of the shard that contains the ID.
+func (p *Partition) ShardOfID(id uint64) int {
+	if p.shards == 1 {
+		return 0
+	}
+
+	if p.mode == Interleaved {
+		return int(id % p.shards)
+	}
+
+	// The first rest shards have size+1 IDs.
+	large := p.rest * (p.size + 1)
+	if id < large {
+		return int(id / (p.size + 1))
+	}
+
+	return int(p.rest + (id-large)/p.size)
+}
+
+// Shard is an allocator of the keys of one shard of the partition,
+// it hands out the keys of the shard in the order of IDs.
+//
+// The allocator is safe for concurrent use by multiple goroutines.
+// It should be created by the Shard method of the Partition only.
+type Shard struct {
+	ls    *Locksmith
+	index int
+	first uint64 // first ID of the shard
+	step  uint64 // distance between IDs of the shard
+	last  uint64 // number of IDs of the shard minus one
+
+	mu        sync.Mutex
+	next      uint64 // number of allocated IDs
+	exhausted bool   // true if all IDs are allocated
+}
+
+// Index returns the index of the shard in the partition.
+func (s *Shard) Index() int {
+	return s.index
+}
+
+// Total returns the number of IDs of the shard. If the shard contains
+// the whole uint64 range the method returns MaxUint64, like the Total
+// method of the Locksmith.
+func (s *Shard) Total() uint64 {
+	if s.last == math.MaxUint64 {
+		return math.MaxUint64
+	}
+
+	return s.last + 1
+}
+
+// NextID returns the next unallocated ID of the shard.
+// It returns an error if all IDs of the shard are allocated.
+func (s *Shard) NextID() (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.exhausted {
+		return 0, fmt.Errorf("the %d shard is exhausted", s.index)
+	}
+
+	id := s.first + s.next*s.step
+	if s.next == s.last {
+		s.exhausted = true
+	} else {
+		s.next++
+	}
+
+	return id, nil
+}
+
+// Next returns the key of the next unallocated ID of the shard.
+func (s *Shard) Next() (string, error) {
+	id, err := s.NextID()
+	if err != nil {
+		return "", err
+	}
+
+	return s.ls.Marshal(id)
+}
+
+// Checkpoint returns the number of allocated IDs, it's the state
+// of the allocator for the Resume method.
+func (s *Shard) Checkpoint() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.exhausted {
+		return s.next + 1
+	}
+
+	return s.next
+}
+
+// Resume sets the number of allocated IDs, the allocation continues
+// from the checkpoint.
+func (s *Shard) Resume(checkpoint uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case checkpoint <= s.last:
+		s.next, s.exhausted = checkpoint, false
+	case checkpoint == s.last+1 && s.last != math.MaxUint64:
+		s.next, s.exhausted = s.last, true
+	default:
+		return errors.New("the checkpoint is out of range of the shard")
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package key
+
+import (
+	"math"
+	"testing"
+)
+
+// TestPartition tests that the shards are disjoint and cover
+// the whole key space.
+func TestPartition(t *testing.T) {
+	ls, _ := New("abc", 3)
+
+	for _, mode := range []PartitionMode{Contiguous, Interleaved} {
+		for _, n := range []int{1, 2, 4, 5, 27} {
+			p, err := ls.Partition(n, mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			seen := make(map[uint64]bool)
+			for i := 0; i < p.Shards(); i++ {
+				shard, err := p.Shard(i)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				count := uint64(0)
+				for {
+					key, err := shard.Next()
+					if err != nil {
+						break
+					}
+
+					id, _ := ls.Unmarshal(key)
+					if seen[id] {
+						t.Fatalf("mode=%d n=%d: duplicate ID %d",
+							mode, n, id)
+					}
+					seen[id] = true
+					count++
+
+					if got, _ := p.ShardOf(key); got != i {
+						t.Fatalf("mode=%d n=%d: ShardOf(%q) = %d, "+
+							"want %d", mode, n, key, got, i)
+					}
+				}
+
+				if count != shard.Total() {
+					t.Errorf("mode=%d n=%d: shard %d has %d keys, "+
+						"Total() = %d", mode, n, i, count, shard.Total())
+				}
+			}
+
+			if len(seen) != 27 {
+				t.Errorf("mode=%d n=%d: %d keys, want 27",
+					mode, n, len(seen))
+			}
+		}
+	}
+}
+
+// TestPartitionErrors tests the invalid partitions.
+func TestPartitionErrors(t *testing.T) {
+	ls, _ := New("abc", 3)
+
+	if _, err := ls.Partition(0, Contiguous); err == nil {
+		t.Error("Partition(0) should return an error")
+	}
+
+	if _, err := ls.Partition(28, Contiguous); err == nil {
+		t.Error("Partition(28) should return an error for 27 keys")
+	}
+
+	if _, err := ls.Partition(2, PartitionMode(5)); err == nil {
+		t.Error("Partition() with unknown mode should return an error")
+	}
+
+	p, _ := ls.Partition(3, Interleaved)
+	if _, err := p.Shard(3); err == nil {
+		t.Error("Shard(3) should return an error")
+	}
+}
+
+// TestPartitionFullRange tests the partition of the whole uint64 range.
+func TestPartitionFullRange(t *testing.T) {
+	ls, _ := New("abc")
+
+	p, err := ls.Partition(3, Contiguous)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last, _ := p.Shard(2)
+	last.Resume(last.Total() - 1)
+	if id, _ := last.NextID(); id != math.MaxUint64 {
+		t.Errorf("the last ID is %d, want MaxUint64", id)
+	}
+
+	if _, err := last.NextID(); err == nil {
+		t.Error("NextID() should return an error for exhausted shard")
+	}
+
+	if got := p.ShardOfID(math.MaxUint64); got != 2 {
+		t.Errorf("ShardOfID(MaxUint64) = %d, want 2", got)
+	}
+
+	single, _ := ls.Partition(1, Interleaved)
+	shard, _ := single.Shard(0)
+	if shard.Total() != math.MaxUint64 {
+		t.Errorf("Total() = %d, want MaxUint64", shard.Total())
+	}
+}
+
+// TestShardResume tests the Checkpoint and Resume methods.
+func TestShardResume(t *testing.T) {
+	ls, _ := New("abc", 3)
+	p, _ := ls.Partition(4, Interleaved)
+	shard, _ := p.Shard(1)
+
+	shard.NextID()
+	shard.NextID()
+	if got := shard.Checkpoint(); got != 2 {
+		t.Errorf("Checkpoint() = %d, want 2", got)
+	}
+
+	other, _ := p.Shard(1)
+	if err := other.Resume(shard.Checkpoint()); err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := shard.NextID()
+	b, _ := other.NextID()
+	if a != b || a != 9 {
+		t.Errorf("NextID() = %d and %d, want 9", a, b)
+	}
+
+	if err := other.Resume(shard.Total() + 1); err == nil {
+		t.Error("Resume() should return an error for out of range")
+	}
+
+	other.Resume(shard.Total())
+	if _, err := other.NextID(); err == nil {
+		t.Error("NextID() should return an error for exhausted shard")
+	}
+}
@@ -0,0 +1,168 @@
+package key
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// Consonants is the alphabet of consonants used in proquints,
+	// each consonant encodes 4 bits.
+	Consonants = "bdfghjklmnprstvz"
+
+	// Vowels is the alphabet of vowels used in proquints,
+	// each vowel encodes 2 bits.
+	Vowels = "aiou"
+)
+
+// DefaultClasses returns the default character classes for
+// the NewPattern function: 'c' for Consonants and 'v' for Vowels.
+func DefaultClasses() map[rune]string {
+	return map[rune]string{'c': Consonants, 'v': Vowels}
+}
+
+// NewPattern returns a new Locksmith object for the fixed-size keys
+// where each position of the key has its own alphabet, for example the
+// pronounceable keys with alternating consonants and vowels.
+//
+//   - pattern is a sequence of class names, one per position of the key,
+//     for example "cvcvc".
+//
+//   - classes maps the class names to their alphabets. If classes is nil,
+//     the DefaultClasses are used.
+//
+// The key is the mixed-radix number, the last position of the key is the
+// least significant digit. So the total number of keys is the product of
+// the sizes of the alphabets of all positions.
+//
+// Example usage:
+//
+//	ls, err := NewPattern("cvcvc", nil)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	key, _ := ls.Marshal(10)
+//	fmt.Println(key) // Output: "babap"
+func NewPattern(pattern string, classes map[rune]string) (*Locksmith, error) {
+	if pattern == "" {
+		return &Locksmith{}, errorf(ErrEmptyAlphabet, "blank pattern string")
+	}
+
+	if classes == nil {
+		classes = DefaultClasses()
+	}
+
+	// Each class is parsed once, the positions of the same
+	// class share the same alphabet.
+	parsed := make(map[rune]*position, len(classes))
+	locksmith := &Locksmith{
+		indexOf: make(map[rune]int),
+		total:   uint64(math.MaxUint64),
+	}
+
+	for _, class := range pattern {
+		p, ok := parsed[class]
+		if !ok {
+			alphabet, ok := classes[class]
+			if !ok {
+				return &Locksmith{}, fmt.Errorf(
+					"the %c class isn't defined",
+					class,
+				)
+			}
+
+			p = &position{
+				chars:   []rune(alphabet),
+				indexOf: make(map[rune]int),
+			}
+
+			if len(p.chars) < 2 {
+				return &Locksmith{}, fmt.Errorf("the %c class must "+
+					"contain at least 2 chars", class)
+			}
+
+			for i, char := range p.chars {
+				if _, ok := p.indexOf[char]; ok {
+					return &Locksmith{}, errorf(ErrDuplicateChar,
+						"the %c item is repeated in the %c class",
+						char, class,
+					)
+				}
+
+				p.indexOf[char] = i
+
+				// The alphabet of the Locksmith is the union
+				// of the characters of all classes.
+				if _, ok := locksmith.indexOf[char]; !ok {
+					locksmith.indexOf[char] = len(locksmith.alphabet)
+					locksmith.alphabet = append(locksmith.alphabet, char)
+				}
+			}
+
+			parsed[class] = p
+		}
+
+		locksmith.pattern = append(locksmith.pattern, p)
+	}
+
+	locksmith.size = uint64(len(locksmith.pattern))
+
+	// The total is the product of the radixes of all positions,
+	// limited to MaxUint64.
+	total := uint64(1)
+	for i := range locksmith.pattern {
+		if total = mul(total, locksmith.base(i)); total == 0 {
+			break
+		}
+	}
+
+	if total != 0 {
+		locksmith.total = total
+	}
+
+	return locksmith, nil
+}
+
+// NewProquint returns a new Locksmith object for the proquint keys
+// (PRO-nouncable QUINT-uplets) like "lusab-babad".
+//
+// Each quint of five characters encodes 16 bits as the consonant-vowel
+// pattern "cvcvc", the quints are separated by the dash. The chunks
+// argument is the number of quints in the key from 1 to 4, for
+// example 2 for the 32-bit IDs and 4 for the 64-bit IDs.
+//
+// Example usage:
+//
+//	ls, _ := NewProquint(2)
+//	key, _ := ls.Marshal(0x7F000001) // 127.0.0.1
+//	fmt.Println(key)                 // Output: "lusab-babad"
+func NewProquint(chunks int) (*Locksmith, error) {
+	if chunks < 1 || chunks > 4 {
+		return &Locksmith{}, fmt.Errorf("incorrect number of "+
+			"proquint chunks %d, must be from 1 to 4", chunks)
+	}
+
+	ls, err := NewPattern(strings.Repeat("cvcvc", chunks), nil)
+	if err != nil {
+		return &Locksmith{}, err
+	}
+
+	return ls.With(WithGroups(5, '-'))
+}
+
+// Pattern returns the alphabets of each position of the patterned key,
+// it returns nil for the Locksmith created by the New function.
+func (ls *Locksmith) Pattern() []string {
+	if ls.pattern == nil {
+		return nil
+	}
+
+	result := make([]string, len(ls.pattern))
+	for i, p := range ls.pattern {
+		result[i] = string(p.chars)
+	}
+
+	return result
+}
@@ -0,0 +1,182 @@
+package key
+
+import (
+	"math"
+	"testing"
+)
+
+// TestNewPattern tests NewPattern function.
+func TestNewPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		classes map[rune]string
+	}{
+		{"blank pattern", "", nil},
+		{"unknown class", "cvx", nil},
+		{"short class", "cv", map[rune]string{'c': "b", 'v': "aiou"}},
+		{"duplicates", "cv", map[rune]string{'c': "bdb", 'v': "aiou"}},
+	}
+
+	for _, test := range tests {
+		if _, err := NewPattern(test.pattern, test.classes); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+
+	ls, err := NewPattern("cvcvc", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ls.Size() != 5 {
+		t.Errorf("expected size to be 5, got %d", ls.Size())
+	}
+
+	if ls.Total() != 1<<16 {
+		t.Errorf("expected total to be %d, got %d", 1<<16, ls.Total())
+	}
+
+	if ls.Alphabet() != Consonants+Vowels {
+		t.Errorf("unexpected alphabet %s", ls.Alphabet())
+	}
+
+	pattern := ls.Pattern()
+	if len(pattern) != 5 || pattern[0] != Consonants || pattern[1] != Vowels {
+		t.Errorf("unexpected pattern %v", pattern)
+	}
+}
+
+// TestPatternMarshal tests Marshal and Unmarshal of the patterned keys.
+func TestPatternMarshal(t *testing.T) {
+	ls, err := NewPattern("cvcvc", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		id  uint64
+		key string
+	}{
+		{0, "babab"},
+		{10, "babap"},
+		{16, "babib"},
+		{1<<16 - 1, "zuzuz"},
+	}
+
+	for _, test := range tests {
+		key, err := ls.Marshal(test.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if key != test.key {
+			t.Errorf("expected %q but %q", test.key, key)
+		}
+
+		id, err := ls.Unmarshal(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id != test.id {
+			t.Errorf("expected %d but %d", test.id, id)
+		}
+	}
+
+	if _, err := ls.Marshal(1 << 16); err == nil {
+		t.Error("expected an error for the large ID")
+	}
+
+	// The vowel at the consonant position.
+	if _, err := ls.Unmarshal("aabab"); err == nil {
+		t.Error("expected an error for the wrong char")
+	}
+
+	// Custom classes.
+	ls, err = NewPattern("dl", map[rune]string{'d': "01", 'l': "abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key, _ := ls.Marshal(5); key != "1c" {
+		t.Errorf("expected %q but %q", "1c", key)
+	}
+}
+
+// TestProquint tests the proquint keys against the examples
+// from the proquint specification.
+func TestProquint(t *testing.T) {
+	tests := []struct {
+		ip  uint64
+		key string
+	}{
+		{127<<24 | 1, "lusab-babad"},
+		{63<<24 | 84<<16 | 220<<8 | 193, "gutih-tugad"},
+		{63<<24 | 118<<16 | 7<<8 | 35, "gutuk-bisog"},
+		{140<<24 | 98<<16 | 193<<8 | 141, "mudof-sakat"},
+		{64<<24 | 255<<16 | 6<<8 | 200, "haguz-biram"},
+		{128<<24 | 30<<16 | 52<<8 | 45, "mabiv-gibot"},
+		{147<<24 | 67<<16 | 119<<8 | 2, "natag-lisaf"},
+		{212<<24 | 58<<16 | 253<<8 | 68, "tibup-zujah"},
+		{216<<24 | 35<<16 | 68<<8 | 215, "tobog-higil"},
+		{216<<24 | 68<<16 | 232<<8 | 21, "todah-vobij"},
+		{198<<24 | 81<<16 | 129<<8 | 136, "sinid-makam"},
+		{12<<24 | 110<<16 | 110<<8 | 204, "budov-kuras"},
+	}
+
+	ls, err := NewProquint(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		key, err := ls.Marshal(test.ip)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if key != test.key {
+			t.Errorf("expected %q but %q", test.key, key)
+		}
+
+		id, err := ls.Unmarshal(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id != test.ip {
+			t.Errorf("expected %d but %d", test.ip, id)
+		}
+	}
+
+	// The separator is required.
+	if _, err := ls.Unmarshal("lusabbabad"); err == nil {
+		t.Error("expected an error for the missing separator")
+	}
+
+	// Whole uint64 range for four chunks.
+	ls, err = NewProquint(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ls.Marshal(math.MaxUint64)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key != "zuzuz-zuzuz-zuzuz-zuzuz" {
+		t.Errorf("unexpected key %q", key)
+	}
+
+	if id, _ := ls.Unmarshal(key); id != math.MaxUint64 {
+		t.Errorf("expected %d but %d", uint64(math.MaxUint64), id)
+	}
+
+	for _, chunks := range []int{0, 5} {
+		if _, err := NewProquint(chunks); err == nil {
+			t.Errorf("expected an error for %d chunks", chunks)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package key
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+)
+
+// The rounds is the number of rounds of the Feistel network.
+const rounds = 8
+
+// Permutation is a keyed pseudo-random permutation of the range [0, n),
+// i.e. it maps each number of the range to the unique number of the same
+// range, and can be inverted. Different secrets give different orders.
+//
+// It's the balanced Feistel network over the smallest even number of
+// bits that covers the range, the values outside the range are walked
+// through the network again (cycle walking). The permutation is
+// stateless, so it uses a constant amount of memory for any range.
+//
+// The Permutation is safe for concurrent use by multiple goroutines.
+type Permutation struct {
+	n    uint64           // size of the range, MaxUint64 for 2^64
+	half uint             // number of bits in each half of the value
+	keys [rounds][32]byte // keys of the rounds
+}
+
+// NewPermutation returns a new permutation of the range [0, n) for the
+// secret. The n as MaxUint64 means the whole uint64 range, like in the
+// Total method of the Locksmith.
+//
+// Example usage:
+//
+//	ls, _ := New(Base58, 8)
+//	p, err := NewPermutation(ls.Total(), []byte("secret"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	key, _ := ls.Marshal(p.Apply(1)) // non-sequential key of the ID 1
+func NewPermutation(n uint64, secret []byte) (*Permutation, error) {
+	if n == 0 {
+		return &Permutation{}, errors.New("blank permutation range")
+	}
+
+	if len(secret) == 0 {
+		return &Permutation{}, errors.New("blank permutation secret")
+	}
+
+	p := &Permutation{n: n, half: 32}
+	if n != math.MaxUint64 {
+		size := uint(bits.Len64(n - 1)) // bits of the largest value
+		p.half = (size + 1) / 2
+		if p.half == 0 {
+			p.half = 1
+		}
+	}
+
+	for i := range p.keys {
+		h := sha256.New()
+		h.Write([]byte{byte(i)})
+		h.Write(secret)
+		copy(p.keys[i][:], h.Sum(nil))
+	}
+
+	return p, nil
+}
+
+// Apply returns the position of the i number in the permutation.
+// The i must be in the range [0, n).
+func (p *Permutation) Apply(i uint64) uint64 {
+	v := p.encrypt(i)
+	for !p.fits(v) {
+		v = p.encrypt(v)
+	}
+
+	return v
+}
+
+// Invert returns the number which is at the v position of the
+// permutation, it's the inverse of the Apply method.
+func (p *Permutation) Invert(v uint64) uint64 {
+	i := p.decrypt(v)
+	for !p.fits(i) {
+		i = p.decrypt(i)
+	}
+
+	return i
+}
+
+// The fits returns true if the value is in the range of permutation.
+func (p *Permutation) fits(v uint64) bool {
+	return v < p.n || p.n == math.MaxUint64
+}
+
+// The encrypt applies the Feistel network to the value.
+func (p *Permutation) encrypt(v uint64) uint64 {
+	mask := uint64(1)<<p.half - 1
+	l, r := v>>p.half&mask, v&mask
+	for i := 0; i < rounds; i++ {
+		l, r = r, l^p.round(i, r)&mask
+	}
+
+	return l<<p.half | r
+}
+
+// The decrypt applies the Feistel network in the reverse order,
+// it's the inverse of the encrypt method.
+func (p *Permutation) decrypt(v uint64) uint64 {
+	mask := uint64(1)<<p.half - 1
+	l, r := v>>p.half&mask, v&mask
+	for i := rounds - 1; i >= 0; i-- {
+		l, r = r^p.round(i, l)&mask, l
+	}
+
+	return l<<p.half | r
+}
+
+// The round is the round function of the Feistel network.
+func (p *Permutation) round(i int, v uint64) uint64 {
+	var buf [40]byte
+
+	copy(buf[:32], p.keys[i][:])
+	binary.BigEndian.PutUint64(buf[32:], v)
+	sum := sha256.Sum256(buf[:])
+
+	return binary.BigEndian.Uint64(sum[:8])
+}
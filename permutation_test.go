@@ -0,0 +1,90 @@
+package key
+
+import (
+	"math"
+	"testing"
+)
+
+// TestNewPermutation tests the NewPermutation function.
+func TestNewPermutation(t *testing.T) {
+	tests := []struct {
+		name   string
+		n      uint64
+		secret string
+		err    bool
+	}{
+		{"Blank range", 0, "secret", true},
+		{"Blank secret", 10, "", true},
+		{"Single value", 1, "secret", false},
+		{"Full range", math.MaxUint64, "secret", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewPermutation(test.n, []byte(test.secret))
+			if (err != nil) != test.err {
+				t.Errorf("NewPermutation() error = %v, want error %v",
+					err, test.err)
+			}
+		})
+	}
+}
+
+// TestPermutationBijection tests that the permutation maps each number
+// of the range to the unique number of the range and can be inverted.
+func TestPermutationBijection(t *testing.T) {
+	for _, n := range []uint64{1, 2, 3, 7, 16, 100, 1000, 4097} {
+		p, err := NewPermutation(n, []byte("secret"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		seen := make(map[uint64]bool, n)
+		for i := uint64(0); i < n; i++ {
+			v := p.Apply(i)
+			if v >= n {
+				t.Fatalf("n=%d: Apply(%d) = %d, out of range", n, i, v)
+			}
+
+			if seen[v] {
+				t.Fatalf("n=%d: Apply(%d) = %d, duplicate", n, i, v)
+			}
+			seen[v] = true
+
+			if got := p.Invert(v); got != i {
+				t.Fatalf("n=%d: Invert(%d) = %d, want %d", n, v, got, i)
+			}
+		}
+	}
+}
+
+// TestPermutationFullRange tests the permutation of the whole uint64 range.
+func TestPermutationFullRange(t *testing.T) {
+	p, err := NewPermutation(math.MaxUint64, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, i := range []uint64{0, 1, 1 << 32, math.MaxUint64} {
+		if got := p.Invert(p.Apply(i)); got != i {
+			t.Errorf("Invert(Apply(%d)) = %d", i, got)
+		}
+	}
+}
+
+// TestPermutationSecret tests that different secrets give different orders.
+func TestPermutationSecret(t *testing.T) {
+	a, _ := NewPermutation(1000, []byte("first"))
+	b, _ := NewPermutation(1000, []byte("second"))
+
+	same := 0
+	for i := uint64(0); i < 1000; i++ {
+		if a.Apply(i) == b.Apply(i) {
+			same++
+		}
+	}
+
+	if same > 50 {
+		t.Errorf("%d of 1000 positions are the same", same)
+	}
+}
@@ -0,0 +1,152 @@
+package key
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// The entropy is the source of randomness for the random keys.
+var entropy io.Reader = rand.Reader
+
+// Random returns a uniformly random key and its ID.
+//
+// The ID is taken from the crypto/rand source in the range [0, Total)
+// without the modulo bias. For the dynamic size the ID can be any uint64
+// value, so the length of the key varies, use the RandomLength method to
+// get the key of the specified length.
+//
+// Example usage:
+//
+//	ls, _ := New(Base62, 16)
+//	token, id, err := ls.Random()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (ls *Locksmith) Random() (string, uint64, error) {
+	if ls.total == math.MaxUint64 {
+		return ls.random(0, 0)
+	}
+
+	return ls.random(0, ls.total)
+}
+
+// RandomLength returns a uniformly random key of the specified length
+// and its ID. The length doesn't include the group separators and the
+// tweak character of the blocklist.
+//
+// For the dynamic size the key has no leading padding characters, i.e.
+// the ID is in the range [L^(length-1), L^length), where L is the size of
+// the alphabet. For the fixed size the length must be equal to the size.
+func (ls *Locksmith) RandomLength(length int) (string, uint64, error) {
+	if length < 1 {
+		return "", 0, fmt.Errorf("incorrect key length %d", length)
+	}
+
+	if ls.size != 0 {
+		if uint64(length) != ls.size {
+			return "", 0, errorf(ErrInvalidLength, "invalid key length, "+
+				"must be %d char(s) but %d char(s)", ls.size, length)
+		}
+
+		return ls.Random()
+	}
+
+	if length == 1 {
+		return ls.random(0, ls.base(0))
+	}
+
+	// The keys of the length are in the range [from, to), the to
+	// is zero if it overflows uint64, so the to-from is still the
+	// number of keys in the range.
+	from, ok := capacity(ls.base(0), length-1)
+	if !ok {
+		return "", 0, fmt.Errorf("the %d length is too large "+
+			"for uint64 ID", length)
+	}
+
+	to, _ := capacity(ls.base(0), length)
+	return ls.random(from, to-from)
+}
+
+// RandomN returns n distinct uniformly random keys and their IDs.
+// The keys are generated like by the Random method.
+func (ls *Locksmith) RandomN(n int) ([]string, []uint64, error) {
+	if n < 0 {
+		return nil, nil, fmt.Errorf("incorrect number of keys %d", n)
+	}
+
+	if ls.total != math.MaxUint64 && uint64(n) > ls.total {
+		return nil, nil, fmt.Errorf("can't generate %d distinct keys "+
+			"from %d keys", n, ls.total)
+	}
+
+	keys, ids := make([]string, 0, n), make([]uint64, 0, n)
+	seen := make(map[uint64]struct{}, n)
+	for len(ids) < n {
+		key, id, err := ls.Random()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if _, ok := seen[id]; ok {
+			continue
+		}
+
+		seen[id] = struct{}{}
+		keys, ids = append(keys, key), append(ids, id)
+	}
+
+	return keys, ids, nil
+}
+
+// The random returns a random key with ID in the range [from, from+n),
+// the zero n means the whole uint64 range.
+func (ls *Locksmith) random(from, n uint64) (string, uint64, error) {
+	v, err := randomBelow(entropy, n)
+	if err != nil {
+		return "", 0, err
+	}
+
+	key, err := ls.Marshal(from + v)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return key, from + v, nil
+}
+
+// The randomBelow returns a uniformly random number in the range [0, n),
+// the zero n means the whole uint64 range.
+//
+// The values that can't be evenly distributed among n are rejected,
+// so the result has no modulo bias.
+func randomBelow(r io.Reader, n uint64) (uint64, error) {
+	var buf [8]byte
+
+	// The rest is the 2^64 mod n, i.e. the number of values
+	// at the end of the uint64 range that cause the bias.
+	var rest uint64
+	if n != 0 {
+		rest = (math.MaxUint64%n + 1) % n
+	}
+
+	for {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return 0, err
+		}
+
+		v := binary.BigEndian.Uint64(buf[:])
+		if v > math.MaxUint64-rest {
+			continue
+		}
+
+		if n == 0 {
+			return v, nil
+		}
+
+		return v % n, nil
+	}
+}
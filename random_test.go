@@ -0,0 +1,141 @@
+package key
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestRandomBelow tests the private randomBelow function.
+func TestRandomBelow(t *testing.T) {
+	// The values from the biased tail of the range are rejected:
+	// 2^64 mod 10 is 6, so the last 6 values are skipped.
+	var buf bytes.Buffer
+	for _, v := range []uint64{math.MaxUint64, math.MaxUint64 - 5, 123} {
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], v)
+		buf.Write(b[:])
+	}
+
+	v, err := randomBelow(&buf, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v != 3 {
+		t.Errorf("expected 3 but %d", v)
+	}
+
+	// The exhausted source.
+	if _, err := randomBelow(&buf, 10); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF but %v", err)
+	}
+
+	// The whole range.
+	buf.Write([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	if v, _ := randomBelow(&buf, 0); v != math.MaxUint64 {
+		t.Errorf("expected %d but %d", uint64(math.MaxUint64), v)
+	}
+}
+
+// TestRandom tests Random method.
+func TestRandom(t *testing.T) {
+	ls, _ := New("abc", 2)
+	counts := make(map[uint64]int)
+	for i := 0; i < 9000; i++ {
+		key, id, err := ls.Random()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id >= ls.Total() {
+			t.Fatalf("the %d ID is out of range", id)
+		}
+
+		if r, _ := ls.Unmarshal(key); r != id {
+			t.Fatalf("%s: expected %d but %d", key, id, r)
+		}
+
+		counts[id]++
+	}
+
+	// Each of 9 IDs is expected about 1000 times.
+	for id := uint64(0); id < 9; id++ {
+		if counts[id] < 800 || counts[id] > 1200 {
+			t.Errorf("the %d ID is generated %d times", id, counts[id])
+		}
+	}
+
+	// The dynamic size.
+	ls, _ = New("abc")
+	if _, _, err := ls.Random(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestRandomLength tests RandomLength method.
+func TestRandomLength(t *testing.T) {
+	ls, _ := New(Base62)
+	for _, length := range []int{1, 2, 5, 10, 11} {
+		for i := 0; i < 100; i++ {
+			key, _, err := ls.RandomLength(length)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if l := utf8.RuneCountInString(key); l != length {
+				t.Fatalf("expected length %d but %d: %s", length, l, key)
+			}
+		}
+	}
+
+	if _, _, err := ls.RandomLength(12); err == nil {
+		t.Error("expected an error for the length out of uint64")
+	}
+
+	if _, _, err := ls.RandomLength(0); err == nil {
+		t.Error("expected an error for the zero length")
+	}
+
+	ls, _ = New(Base62, 8)
+	if key, _, err := ls.RandomLength(8); err != nil || len(key) != 8 {
+		t.Errorf("unexpected key %q (%v)", key, err)
+	}
+
+	if _, _, err := ls.RandomLength(7); err == nil {
+		t.Error("expected an error for the length other than size")
+	}
+}
+
+// TestRandomN tests RandomN method.
+func TestRandomN(t *testing.T) {
+	ls, _ := New("abc", 2)
+	keys, ids, err := ls.RandomN(9)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != 9 || len(ids) != 9 {
+		t.Fatalf("expected 9 keys but %d", len(keys))
+	}
+
+	seen := make(map[string]bool)
+	for i, key := range keys {
+		if seen[key] {
+			t.Errorf("the %q key is duplicated", key)
+		}
+		seen[key] = true
+
+		if r, _ := ls.Unmarshal(key); r != ids[i] {
+			t.Errorf("%s: expected %d but %d", key, ids[i], r)
+		}
+	}
+
+	if _, _, err := ls.RandomN(10); err == nil {
+		t.Error("expected an error for too many keys")
+	}
+}
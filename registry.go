@@ -0,0 +1,202 @@
+package key
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registry maps the names of entities to their Locksmith objects,
+// so the application can refer to the key spaces by names.
+//
+// The Registry detects the entity of the key by its prefix (see the
+// WithPrefix option), so the prefixes of the registered Locksmith
+// objects mustn't be the prefixes of each other.
+//
+// The Registry is safe for concurrent use by multiple goroutines.
+// The zero value is an empty registry ready to use.
+type Registry struct {
+	mu    sync.RWMutex
+	items map[string]*Locksmith
+}
+
+// NewRegistry returns a new registry of the Locksmith objects
+// created from the configurations by the FromConfig function.
+//
+// Example usage:
+//
+//	r, err := NewRegistry(map[string]Config{
+//	    "order": {Alphabet: Base58, Size: 8, Prefix: "ord_"},
+//	    "user":  {Alphabet: Base58, Size: 8, Prefix: "usr_"},
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	key, _ := r.Marshal("order", 10)    // "ord_1111111B"
+//	name, id, _ := r.Detect(key)        // "order", 10
+func NewRegistry(configs map[string]Config) (*Registry, error) {
+	r := &Registry{}
+	if err := r.Load(configs); err != nil {
+		return &Registry{}, err
+	}
+
+	return r, nil
+}
+
+// Register adds the Locksmith under the name. It returns an error
+// if the name is already registered or the prefix of the Locksmith
+// conflicts with the prefix of the registered one.
+func (r *Registry) Register(name string, ls *Locksmith) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.register(map[string]*Locksmith{name: ls})
+}
+
+// Load adds the Locksmith objects created from the configurations.
+// If any configuration is invalid, no Locksmith is added.
+func (r *Registry) Load(configs map[string]Config) error {
+	items := make(map[string]*Locksmith, len(configs))
+	for name, cfg := range configs {
+		ls, err := FromConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("the %s Locksmith: %w", name, err)
+		}
+
+		items[name] = ls
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.register(items)
+}
+
+// The register adds the items to the registry, it must be
+// called under the write lock.
+func (r *Registry) register(items map[string]*Locksmith) error {
+	// The names are sorted to report the same conflict every time.
+	names := make([]string, 0, len(items))
+	for name := range items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	merged := make(map[string]*Locksmith, len(r.items)+len(items))
+	for name, ls := range r.items {
+		merged[name] = ls
+	}
+
+	for _, name := range names {
+		ls := items[name]
+		if name == "" || ls == nil {
+			return errors.New("the Locksmith must have the name")
+		}
+
+		if _, ok := merged[name]; ok {
+			return fmt.Errorf("the %s Locksmith is already registered",
+				name)
+		}
+
+		for other, o := range merged {
+			if ls.prefix == "" || o.prefix == "" {
+				continue
+			}
+
+			if strings.HasPrefix(ls.prefix, o.prefix) ||
+				strings.HasPrefix(o.prefix, ls.prefix) {
+				return fmt.Errorf("the %q prefix of the %s Locksmith "+
+					"conflicts with the %q prefix of the %s Locksmith",
+					ls.prefix, name, o.prefix, other)
+			}
+		}
+
+		merged[name] = ls
+	}
+
+	r.items = merged
+	return nil
+}
+
+// Get returns the Locksmith registered under the name.
+func (r *Registry) Get(name string) (*Locksmith, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ls, ok := r.items[name]
+	return ls, ok
+}
+
+// Names returns the sorted names of the registered Locksmith objects.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.items))
+	for name := range r.items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Marshal converts the ID into the key by the Locksmith of the name.
+func (r *Registry) Marshal(name string, id uint64) (string, error) {
+	ls, err := r.lookup(name)
+	if err != nil {
+		return "", err
+	}
+
+	return ls.Marshal(id)
+}
+
+// Unmarshal converts the key into the ID by the Locksmith of the name.
+func (r *Registry) Unmarshal(name, key string) (uint64, error) {
+	ls, err := r.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return ls.Unmarshal(key)
+}
+
+// Detect finds the Locksmith by the prefix of the key and returns
+// its name and the ID of the key. The Locksmith objects without
+// prefix aren't detected.
+func (r *Registry) Detect(key string) (string, uint64, error) {
+	r.mu.RLock()
+	var name string
+	var ls *Locksmith
+	for n, l := range r.items {
+		if l.prefix != "" && strings.HasPrefix(key, l.prefix) {
+			name, ls = n, l
+			break
+		}
+	}
+	r.mu.RUnlock()
+
+	if ls == nil {
+		return "", 0, fmt.Errorf("the %q key has no known prefix", key)
+	}
+
+	id, err := ls.Unmarshal(key)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return name, id, nil
+}
+
+// The lookup returns the Locksmith of the name or an error.
+func (r *Registry) lookup(name string) (*Locksmith, error) {
+	ls, ok := r.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("the %s Locksmith isn't registered", name)
+	}
+
+	return ls, nil
+}
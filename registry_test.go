@@ -0,0 +1,138 @@
+package key
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// TestRegistry tests the Registry.
+func TestRegistry(t *testing.T) {
+	r, err := NewRegistry(map[string]Config{
+		"order": {Alphabet: Base58, Size: 8, Prefix: "ord_"},
+		"user":  {Alphabet: Base58, Size: 8, Prefix: "usr_"},
+		"plain": {Alphabet: "abc"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if names := r.Names(); !reflect.DeepEqual(names,
+		[]string{"order", "plain", "user"}) {
+		t.Errorf("Names() = %v", names)
+	}
+
+	key, err := r.Marshal("order", 10)
+	if err != nil || key != "ord_1111111B" {
+		t.Errorf("Marshal() = %q, %v, want %q", key, err, "ord_1111111B")
+	}
+
+	if id, err := r.Unmarshal("order", key); err != nil || id != 10 {
+		t.Errorf("Unmarshal() = %d, %v, want 10", id, err)
+	}
+
+	if _, err := r.Unmarshal("user", key); err == nil {
+		t.Error("Unmarshal() of the key of other entity should fail")
+	}
+
+	name, id, err := r.Detect("usr_11111121")
+	if err != nil || name != "user" || id != 58 {
+		t.Errorf("Detect() = %q, %d, %v, want user, 58", name, id, err)
+	}
+
+	for _, key := range []string{"bab", "ord_bad", "inv_1111111B"} {
+		if _, _, err := r.Detect(key); err == nil {
+			t.Errorf("Detect(%q) should return an error", key)
+		}
+	}
+
+	if _, err := r.Marshal("invoice", 1); err == nil {
+		t.Error("Marshal() of unknown name should return an error")
+	}
+
+	if _, err := r.Unmarshal("invoice", "a"); err == nil {
+		t.Error("Unmarshal() of unknown name should return an error")
+	}
+
+	if ls, ok := r.Get("plain"); !ok || ls.Alphabet() != "abc" {
+		t.Error("Get() should return the registered Locksmith")
+	}
+}
+
+// TestRegistryConflicts tests the errors of the registration.
+func TestRegistryConflicts(t *testing.T) {
+	var r Registry
+	ls, _ := New("abc")
+	ord, _ := ls.With(WithPrefix("ord_"))
+
+	if err := r.Register("order", ord); err != nil {
+		t.Fatal(err)
+	}
+
+	or, _ := ls.With(WithPrefix("or"))
+	ordx, _ := ls.With(WithPrefix("ord_x"))
+	tests := []struct {
+		name string
+		ls   *Locksmith
+	}{
+		{"order", ls},
+		{"", ls},
+		{"nil", nil},
+		{"short", or},
+		{"long", ordx},
+	}
+
+	for _, test := range tests {
+		if err := r.Register(test.name, test.ls); err == nil {
+			t.Errorf("Register(%q) should return an error", test.name)
+		}
+	}
+
+	// The invalid configuration doesn't add anything.
+	err := r.Load(map[string]Config{
+		"user":    {Alphabet: "abc", Prefix: "usr_"},
+		"invalid": {Alphabet: "aa"},
+	})
+	if err == nil {
+		t.Error("Load() should return an error")
+	}
+
+	if _, ok := r.Get("user"); ok {
+		t.Error("Load() with error shouldn't add the Locksmith")
+	}
+
+	if _, err := NewRegistry(map[string]Config{
+		"a": {Alphabet: "abc", Prefix: "x"},
+		"b": {Alphabet: "abc", Prefix: "xy"},
+	}); err == nil {
+		t.Error("NewRegistry() should return an error for the conflict")
+	}
+}
+
+// TestRegistryConcurrent tests the concurrent use of the Registry.
+func TestRegistryConcurrent(t *testing.T) {
+	var r Registry
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := string(rune('a' + i))
+			ls, _ := New("abc")
+			ls, _ = ls.With(WithPrefix(name + "_"))
+			if err := r.Register(name, ls); err != nil {
+				t.Error(err)
+			}
+
+			for id := uint64(0); id < 100; id++ {
+				key, _ := r.Marshal(name, id)
+				if got, detected, err := r.Detect(key); err != nil ||
+					got != name || detected != id {
+					t.Errorf("Detect(%q) = %q, %d, %v", key, got,
+						detected, err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
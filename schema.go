@@ -0,0 +1,198 @@
+package key
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is the JSON Schema of the string with the key, it's also the
+// string schema of the OpenAPI specification. The lengths are in runes.
+type Schema struct {
+	Type      string `json:"type"`
+	Pattern   string `json:"pattern"`
+	MinLength int    `json:"minLength"`
+	MaxLength int    `json:"maxLength"`
+	Example   string `json:"example,omitempty"`
+}
+
+// Regexp returns the regular expression of the keys of the Locksmith.
+// The expression is anchored and uses the syntax that is common for the
+// regexp package and the ECMA-262 (JSON Schema, OpenAPI, JavaScript),
+// the special characters of the alphabet, prefix and separator are
+// escaped.
+//
+// The expression checks the characters of each position, the prefix,
+// separators and the length of the key. For the dynamic size the key
+// may have up to the number of characters of the MaxUint64 ID, the keys
+// with the leading padding characters match too. The check character
+// and the range of the ID aren't checked, use the Validate method for
+// the full check.
+//
+// Example usage:
+//
+//	ls, _ := New("0123456789abcdef", 8)
+//	ls, _ = ls.With(WithGroups(4, '-'), WithPrefix("id."))
+//	fmt.Println(ls.Regexp()) // Output: ^id\.[0-9a-f]{4}-[0-9a-f]{4}$
+func (ls *Locksmith) Regexp() string {
+	var sb strings.Builder
+	sb.WriteString("^" + regexp.QuoteMeta(ls.prefix))
+
+	sep := regexp.QuoteMeta(string(ls.separator))
+	if ls.size == 0 {
+		class := charClass(ls.alphabet)
+		lo, hi := ls.digits()
+		switch {
+		case ls.group == 0:
+			fmt.Fprintf(&sb, "%s{%d,%d}", class, lo, hi)
+		default:
+			// The groups are full except the last one.
+			fmt.Fprintf(&sb, "(?:%s{%d}%s){0,%d}%s{1,%d}", class, ls.group,
+				sep, (hi-1)/ls.group, class, ls.group)
+		}
+
+		return sb.String() + "$"
+	}
+
+	// The positions of the fixed size key, the consecutive positions
+	// of the same class are joined into one item with the quantifier.
+	n, _ := ls.digits()
+	class, count := "", 0
+	flush := func() {
+		sb.WriteString(class)
+		if count > 1 {
+			fmt.Fprintf(&sb, "{%d}", count)
+		}
+
+		class, count = "", 0
+	}
+
+	for i := 0; i < n; i++ {
+		if ls.group > 0 && i > 0 && i%ls.group == 0 {
+			flush()
+			sb.WriteString(sep)
+		}
+
+		chars, _ := ls.digit(i)
+		if c := charClass(chars); c != class {
+			flush()
+			class = c
+		}
+
+		count++
+	}
+
+	flush()
+	return sb.String() + "$"
+}
+
+// Schema returns the JSON Schema of the string with the key, the pattern
+// is the result of the Regexp method.
+//
+// Example usage:
+//
+//	ls, _ := New(Base58, 8)
+//	data, err := json.Marshal(ls.Schema())
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(string(data))
+//	// Output: {"type":"string","pattern":"^[1-9A-HJ-NP-Za-km-z]{8}$",
+//	// "minLength":8,"maxLength":8,"example":"1111111B"}
+func (ls *Locksmith) Schema() Schema {
+	lo, hi := ls.digits()
+	schema := Schema{
+		Type:      "string",
+		Pattern:   ls.Regexp(),
+		MinLength: ls.length(lo),
+		MaxLength: ls.length(hi),
+	}
+
+	if example, err := ls.Marshal(10); err == nil {
+		schema.Example = example
+	} else if example, err := ls.Marshal(0); err == nil {
+		schema.Example = example
+	}
+
+	return schema
+}
+
+// The digits returns the minimum and maximum number of characters of the
+// key without the prefix and separators, including the tweak character
+// of the blocklist and the check character.
+func (ls *Locksmith) digits() (int, int) {
+	extra := 0
+	if ls.blocklist != nil {
+		extra++
+	}
+
+	if ls.checksum {
+		extra++
+	}
+
+	if ls.size != 0 {
+		return int(ls.size) + extra, int(ls.size) + extra
+	}
+
+	n := 0
+	for v := uint64(math.MaxUint64); v > 0; v /= ls.base(0) {
+		n++
+	}
+
+	return 1 + extra, n + extra
+}
+
+// The length returns the length of the key of the n characters
+// with the prefix and separators, in runes.
+func (ls *Locksmith) length(n int) int {
+	result := utf8.RuneCountInString(ls.prefix) + n
+	if ls.group > 0 {
+		result += (n - 1) / ls.group
+	}
+
+	return result
+}
+
+// The charClass returns the character class of the regular expression
+// that matches the chars, the runs of three and more consecutive chars
+// are written as the ranges.
+func charClass(chars []rune) string {
+	sorted := append([]rune(nil), chars...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] == sorted[j]+1 {
+			j++
+		}
+
+		switch {
+		case j-i >= 2:
+			sb.WriteString(escapeClass(sorted[i]) + "-" +
+				escapeClass(sorted[j]))
+		default:
+			for _, r := range sorted[i : j+1] {
+				sb.WriteString(escapeClass(r))
+			}
+		}
+
+		i = j + 1
+	}
+	sb.WriteByte(']')
+
+	return sb.String()
+}
+
+// The escapeClass escapes the special char of the character class.
+func escapeClass(r rune) string {
+	if strings.ContainsRune(`\]-[^`, r) {
+		return `\` + string(r)
+	}
+
+	return string(r)
+}
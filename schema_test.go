@@ -0,0 +1,115 @@
+package key
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"testing"
+)
+
+// TestRegexp tests Regexp method.
+func TestRegexp(t *testing.T) {
+	hex, _ := New("0123456789abcdef", 8)
+	grouped, _ := hex.With(WithGroups(4, '-'), WithPrefix("id."))
+	special, _ := New(`a-]^\[.`, 3)
+	proquint, _ := NewProquint(2)
+	checked, _ := New("0123456789", 4)
+	checked, _ = checked.With(WithChecksum())
+	dynamic, _ := New("abc")
+
+	tests := []struct {
+		name string
+		ls   *Locksmith
+		want string
+	}{
+		{"fixed", hex, `^[0-9a-f]{8}$`},
+		{"grouped", grouped, `^id\.[0-9a-f]{4}-[0-9a-f]{4}$`},
+		{"special", special, `^[\-.\[-\^a]{3}$`},
+		{"pattern", proquint,
+			`^[bdf-hj-npr-tvz][aiou][bdf-hj-npr-tvz][aiou][bdf-hj-npr-tvz]` +
+				`-[bdf-hj-npr-tvz][aiou][bdf-hj-npr-tvz][aiou][bdf-hj-npr-tvz]$`},
+		{"checksum", checked, `^[0-9]{5}$`},
+		{"dynamic", dynamic, `^[a-c]{1,41}$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ls.Regexp(); got != tt.want {
+				t.Errorf("expected %s but %s", tt.want, got)
+			}
+
+			re := regexp.MustCompile(tt.ls.Regexp())
+			for _, id := range []uint64{0, 10, tt.ls.last()} {
+				if key, _ := tt.ls.Marshal(id); !re.MatchString(key) {
+					t.Errorf("the %q key doesn't match %s", key, re)
+				}
+			}
+		})
+	}
+}
+
+// TestRegexpMatch tests that the keys match the regular expression.
+func TestRegexpMatch(t *testing.T) {
+	dynamic, _ := New(Base58)
+	grouped, _ := dynamic.With(WithGroups(3, '_'), WithPrefix("k+"))
+	blocked, _ := New("abc", 4)
+	blocked, _ = blocked.With(WithBlocklist("cab"), WithGroups(2, '-'))
+	checked, _ := New(Base62, 6)
+	checked, _ = checked.With(WithChecksum(), WithGroups(4, ' '))
+
+	for _, ls := range []*Locksmith{dynamic, grouped, blocked, checked} {
+		re := regexp.MustCompile(ls.Regexp())
+		schema := ls.Schema()
+		ids := []uint64{0, 1, 10, 57, 58, 3364, ls.last() / 2, ls.last()}
+		for _, id := range ids {
+			key, err := ls.Marshal(id)
+			if err != nil {
+				continue
+			}
+
+			if !re.MatchString(key) {
+				t.Errorf("the %q key doesn't match %s", key, re)
+			}
+
+			if l := len([]rune(key)); l < schema.MinLength ||
+				l > schema.MaxLength {
+				t.Errorf("the %q key length is out of [%d, %d]",
+					key, schema.MinLength, schema.MaxLength)
+			}
+		}
+
+		for _, key := range []string{"", "k+", "!!!", "a b"} {
+			if re.MatchString(key) {
+				t.Errorf("the %q key matches %s", key, re)
+			}
+		}
+	}
+}
+
+// TestSchema tests Schema method.
+func TestSchema(t *testing.T) {
+	ls, _ := New(Base58, 8)
+	data, err := json.Marshal(ls.Schema())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"type":"string","pattern":"^[1-9A-HJ-NP-Za-km-z]{8}$",` +
+		`"minLength":8,"maxLength":8,"example":"1111111B"}`
+	if string(data) != want {
+		t.Errorf("expected %s but %s", want, data)
+	}
+
+	dynamic, _ := New("0123456789")
+	dynamic, _ = dynamic.With(WithGroups(3, ','), WithPrefix("№"))
+	schema := dynamic.Schema()
+	if schema.MinLength != 2 || schema.MaxLength != 27 {
+		t.Errorf("expected [2, 27] but [%d, %d]",
+			schema.MinLength, schema.MaxLength)
+	}
+
+	key, _ := dynamic.Marshal(math.MaxUint64)
+	if l := len([]rune(key)); l != schema.MaxLength {
+		t.Errorf("expected %d but %d for %q", schema.MaxLength, l, key)
+	}
+}
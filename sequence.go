@@ -0,0 +1,229 @@
+package key
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Store is a persistent storage of the high-water mark of the sequence,
+// i.e. the number of IDs reserved by the sequence.
+type Store interface {
+	// Load returns the saved mark, or zero if nothing is saved yet.
+	Load() (uint64, error)
+
+	// Save saves the mark, the saved mark must survive the restart.
+	Save(mark uint64) error
+}
+
+// MemoryStore is the in-memory Store, it's useful for tests and for
+// the sequences that don't need to survive the restart.
+//
+// The MemoryStore is safe for concurrent use by multiple goroutines.
+type MemoryStore struct {
+	mu   sync.Mutex
+	mark uint64
+}
+
+// Load returns the saved mark.
+func (s *MemoryStore) Load() (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mark, nil
+}
+
+// Save saves the mark.
+func (s *MemoryStore) Save(mark uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mark = mark
+	return nil
+}
+
+// FileStore is the Store that keeps the mark in the file as a decimal
+// number. The file is replaced atomically: the mark is written into
+// a temporary file, which is synced to the disk and renamed.
+type FileStore struct {
+	Path string // path to the file
+}
+
+// Load returns the saved mark, or zero if the file doesn't exist.
+func (s FileStore) Load() (uint64, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	mark, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sequence file %s: %w", s.Path, err)
+	}
+
+	return mark, nil
+}
+
+// Save saves the mark into the file.
+func (s FileStore) Save(mark uint64) error {
+	dir := filepath.Dir(s.Path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.Path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after the rename
+
+	data := strconv.FormatUint(mark, 10) + "\n"
+	if _, err := tmp.WriteString(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), s.Path); err != nil {
+		return err
+	}
+
+	return syncDir(dir)
+}
+
+// The syncDir syncs the directory to persist the rename. Windows can't
+// sync the directory handle (it fails with the access denied error) and
+// persists the rename itself, so the sync is skipped there.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
+
+// Sequence hands out the keys of the Locksmith in the order of IDs and
+// persists its state in the Store, so the keys aren't repeated after
+// the restart.
+//
+// To avoid the write per allocation, the sequence reserves the block of
+// IDs: it saves the end of the block as the high-water mark and hands
+// out IDs of the block from the memory. The unused IDs of the block are
+// skipped after the restart.
+//
+// The Sequence is safe for concurrent use by multiple goroutines.
+// It should be created by the NewSequence function only.
+type Sequence struct {
+	ls    *Locksmith
+	store Store
+	block uint64
+
+	mu   sync.Mutex
+	next uint64 // next ID to hand out
+	mark uint64 // end of the reserved block
+	done bool   // true if the last ID is handed out
+}
+
+// NewSequence returns a new sequence of the keys of the ls Locksmith
+// that continues from the mark saved in the store. The block is the
+// number of IDs reserved per write to the store, it must be positive.
+//
+// Example usage:
+//
+//	ls, _ := New(Base58, 8)
+//	seq, err := NewSequence(ls, FileStore{Path: "orders.seq"}, 100)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	key, err := seq.Next()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func NewSequence(ls *Locksmith, store Store, block int) (*Sequence, error) {
+	if store == nil {
+		return &Sequence{}, errors.New("nil store")
+	}
+
+	if block < 1 {
+		return &Sequence{}, fmt.Errorf("incorrect block size %d", block)
+	}
+
+	mark, err := store.Load()
+	if err != nil {
+		return &Sequence{}, err
+	}
+
+	return &Sequence{
+		ls:    ls,
+		store: store,
+		block: uint64(block),
+		next:  mark,
+		mark:  mark,
+	}, nil
+}
+
+// NextID returns the next ID of the sequence. It returns an error
+// if the key space is exhausted or the store fails.
+func (s *Sequence) NextID() (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done || !s.ls.fits(s.next) {
+		return 0, errors.New("the key space of the sequence is exhausted")
+	}
+
+	if s.next == s.mark {
+		// Reserve the next block, it's clipped by the key space,
+		// the MaxUint64 mark means the whole uint64 range.
+		mark := uint64(math.MaxUint64)
+		if s.next <= math.MaxUint64-s.block {
+			mark = s.next + s.block
+		}
+
+		if s.ls.total != math.MaxUint64 && mark > s.ls.total {
+			mark = s.ls.total
+		}
+
+		if err := s.store.Save(mark); err != nil {
+			return 0, err
+		}
+
+		s.mark = mark
+	}
+
+	id := s.next
+	if id == math.MaxUint64 {
+		s.done = true
+	} else {
+		s.next++
+	}
+
+	return id, nil
+}
+
+// Next returns the key of the next ID of the sequence.
+func (s *Sequence) Next() (string, error) {
+	id, err := s.NextID()
+	if err != nil {
+		return "", err
+	}
+
+	return s.ls.Marshal(id)
+}
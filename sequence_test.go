@@ -0,0 +1,159 @@
+package key
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// countingStore is the Store that counts the writes.
+type countingStore struct {
+	MemoryStore
+	saves int
+	err   error
+}
+
+// Save saves the mark and counts the writes.
+func (s *countingStore) Save(mark uint64) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	s.saves++
+	return s.MemoryStore.Save(mark)
+}
+
+// TestSequence tests that the sequence hands out the keys in order
+// and reserves the blocks.
+func TestSequence(t *testing.T) {
+	ls, _ := New("abc", 3)
+	store := &countingStore{}
+
+	seq, err := NewSequence(ls, store, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for id := uint64(0); id < 27; id++ {
+		key, err := seq.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, _ := ls.Marshal(id); key != want {
+			t.Fatalf("Next() = %q, want %q", key, want)
+		}
+	}
+
+	if store.saves != 3 {
+		t.Errorf("the store is written %d times, want 3", store.saves)
+	}
+
+	if mark, _ := store.Load(); mark != 27 {
+		t.Errorf("the mark is %d, want 27", mark)
+	}
+
+	if _, err := seq.Next(); err == nil {
+		t.Error("Next() should return an error for exhausted key space")
+	}
+}
+
+// TestSequenceRestart tests that the sequence continues after
+// the reserved block.
+func TestSequenceRestart(t *testing.T) {
+	ls, _ := New(Base58, 8)
+	store := &MemoryStore{}
+
+	seq, _ := NewSequence(ls, store, 100)
+	seq.NextID()
+	seq.NextID()
+
+	seq, _ = NewSequence(ls, store, 100)
+	if id, _ := seq.NextID(); id != 100 {
+		t.Errorf("NextID() after restart = %d, want 100", id)
+	}
+}
+
+// TestSequenceErrors tests the errors of the sequence.
+func TestSequenceErrors(t *testing.T) {
+	ls, _ := New("abc", 3)
+
+	if _, err := NewSequence(ls, nil, 10); err == nil {
+		t.Error("NewSequence() with nil store should return an error")
+	}
+
+	if _, err := NewSequence(ls, &MemoryStore{}, 0); err == nil {
+		t.Error("NewSequence() with zero block should return an error")
+	}
+
+	store := &countingStore{err: errors.New("disk is full")}
+	seq, _ := NewSequence(ls, store, 10)
+	if _, err := seq.NextID(); err == nil {
+		t.Error("NextID() should return the error of the store")
+	}
+}
+
+// TestSequenceConcurrent tests the concurrent allocation.
+func TestSequenceConcurrent(t *testing.T) {
+	ls, _ := New(Base58)
+	seq, _ := NewSequence(ls, &MemoryStore{}, 7)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	seen := make(map[uint64]bool)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				id, err := seq.NextID()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("duplicate ID %d", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != 800 {
+		t.Errorf("%d IDs, want 800", len(seen))
+	}
+}
+
+// TestFileStore tests the FileStore.
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.seq")
+	store := FileStore{Path: path}
+
+	if mark, err := store.Load(); err != nil || mark != 0 {
+		t.Errorf("Load() of missing file = %d, %v", mark, err)
+	}
+
+	if err := store.Save(12345); err != nil {
+		t.Fatal(err)
+	}
+
+	if mark, err := store.Load(); err != nil || mark != 12345 {
+		t.Errorf("Load() = %d, %v, want 12345", mark, err)
+	}
+
+	files, _ := os.ReadDir(filepath.Dir(path))
+	if len(files) != 1 {
+		t.Errorf("%d files in the directory, want 1", len(files))
+	}
+
+	os.WriteFile(path, []byte("garbage"), 0o600)
+	if _, err := store.Load(); err == nil {
+		t.Error("Load() of invalid file should return an error")
+	}
+}
@@ -0,0 +1,180 @@
+package key
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	snowflakeTimeBits     = 41 // about 69 years of milliseconds
+	snowflakeWorkerBits   = 10 // up to 1024 workers
+	snowflakeSequenceBits = 12 // up to 4096 IDs per millisecond
+
+	snowflakeMaxTime     = 1<<snowflakeTimeBits - 1
+	snowflakeMaxWorker   = 1<<snowflakeWorkerBits - 1
+	snowflakeMaxSequence = 1<<snowflakeSequenceBits - 1
+)
+
+// SnowflakeEpoch is the default epoch of the Snowflake generator.
+var SnowflakeEpoch = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// SnowflakeConfig is the configuration of the Snowflake generator.
+type SnowflakeConfig struct {
+	// Epoch is the start of the time of the generator, the zero value
+	// means the SnowflakeEpoch. The time can't be earlier than the epoch.
+	Epoch time.Time
+
+	// Worker is the unique number of the process that generates IDs,
+	// from 0 to 1023.
+	Worker uint64
+
+	// Clock returns the current time, the nil value means time.Now.
+	Clock func() time.Time
+}
+
+// SnowflakeID is the decoded Snowflake ID.
+type SnowflakeID struct {
+	Time     time.Time // time of generation with millisecond precision
+	Worker   uint64    // number of the worker
+	Sequence uint64    // sequence number within the millisecond
+}
+
+// Snowflake is a generator of the time-ordered unique IDs, which are
+// rendered as keys through the Locksmith.
+//
+// The 63-bit ID consists of 41 bits of milliseconds since the epoch,
+// 10 bits of the worker number and 12 bits of the sequence number, so
+// the IDs of different workers never collide, and the IDs of the same
+// worker are strictly increasing.
+//
+// To make the keys sortable as strings, use the fixed-size Locksmith
+// with the alphabet sorted in the code-point order (see NewSortable).
+//
+// The generator is safe for concurrent use by multiple goroutines.
+// It should be created by the NewSnowflake function only.
+type Snowflake struct {
+	ls     *Locksmith
+	epoch  time.Time
+	worker uint64
+	clock  func() time.Time
+
+	mu       sync.Mutex
+	last     int64  // time of the last ID in milliseconds since epoch
+	sequence uint64 // sequence number of the last ID
+}
+
+// NewSnowflake returns a new Snowflake generator that renders IDs
+// through the ls Locksmith. The Locksmith must be able to encode any
+// 63-bit number.
+//
+// Example usage:
+//
+//	ls, _ := New(Base62, 11)
+//	sf, err := NewSnowflake(ls, SnowflakeConfig{Worker: 7})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	key, err := sf.Next()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func NewSnowflake(ls *Locksmith, cfg SnowflakeConfig) (*Snowflake, error) {
+	if ls == nil {
+		return &Snowflake{}, errors.New("nil Locksmith")
+	}
+
+	if !ls.fits(1<<63 - 1) {
+		return &Snowflake{}, errors.New("the Locksmith is too small " +
+			"for 63-bit Snowflake IDs")
+	}
+
+	if cfg.Worker > snowflakeMaxWorker {
+		return &Snowflake{}, fmt.Errorf("the worker must be from 0 to %d",
+			snowflakeMaxWorker)
+	}
+
+	sf := &Snowflake{
+		ls:     ls,
+		epoch:  cfg.Epoch,
+		worker: cfg.Worker,
+		clock:  cfg.Clock,
+		last:   -1,
+	}
+
+	if sf.epoch.IsZero() {
+		sf.epoch = SnowflakeEpoch
+	}
+
+	if sf.clock == nil {
+		sf.clock = time.Now
+	}
+
+	return sf, nil
+}
+
+// NextID returns the next unique ID.
+//
+// If the clock moves backwards, the generator keeps using the time of
+// the last ID, and if the sequence of the millisecond is exhausted, it
+// borrows the next millisecond, so the IDs are still unique and
+// increasing. The generated time catches up with the clock later.
+func (sf *Snowflake) NextID() (uint64, error) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	now := sf.clock().Sub(sf.epoch).Milliseconds()
+	if now < 0 && sf.last < 0 {
+		return 0, errors.New("the clock is before the epoch")
+	}
+
+	switch {
+	case now > sf.last:
+		sf.last, sf.sequence = now, 0
+	case sf.sequence < snowflakeMaxSequence:
+		sf.sequence++ // the same millisecond or the clock regression
+	default:
+		sf.last, sf.sequence = sf.last+1, 0
+	}
+
+	if sf.last > snowflakeMaxTime {
+		return 0, errors.New("the time is out of range of the Snowflake")
+	}
+
+	return uint64(sf.last)<<(snowflakeWorkerBits+snowflakeSequenceBits) |
+		sf.worker<<snowflakeSequenceBits |
+		sf.sequence, nil
+}
+
+// Next returns the key of the next unique ID.
+func (sf *Snowflake) Next() (string, error) {
+	id, err := sf.NextID()
+	if err != nil {
+		return "", err
+	}
+
+	return sf.ls.Marshal(id)
+}
+
+// Parse decodes the key generated by the Snowflake and splits
+// it into the fields.
+func (sf *Snowflake) Parse(key string) (SnowflakeID, error) {
+	id, err := sf.ls.Unmarshal(key)
+	if err != nil {
+		return SnowflakeID{}, err
+	}
+
+	if id > 1<<63-1 {
+		return SnowflakeID{}, fmt.Errorf("the %q key isn't "+
+			"a Snowflake ID", key)
+	}
+
+	ms := int64(id >> (snowflakeWorkerBits + snowflakeSequenceBits))
+	return SnowflakeID{
+		Time:     sf.epoch.Add(time.Duration(ms) * time.Millisecond),
+		Worker:   id >> snowflakeSequenceBits & snowflakeMaxWorker,
+		Sequence: id & snowflakeMaxSequence,
+	}, nil
+}
@@ -0,0 +1,158 @@
+package key
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is the clock for tests that returns the specified time.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Now returns the current time of the clock.
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Add moves the clock by the specified duration.
+func (c *fakeClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// TestNewSnowflake tests NewSnowflake function.
+func TestNewSnowflake(t *testing.T) {
+	small, _ := New(Base62, 8)
+	if _, err := NewSnowflake(small, SnowflakeConfig{}); err == nil {
+		t.Error("expected an error for the small Locksmith")
+	}
+
+	ls, _ := New(Base62, 11)
+	if _, err := NewSnowflake(ls, SnowflakeConfig{Worker: 1024}); err == nil {
+		t.Error("expected an error for the large worker")
+	}
+
+	if _, err := NewSnowflake(nil, SnowflakeConfig{}); err == nil {
+		t.Error("expected an error for the nil Locksmith")
+	}
+}
+
+// TestSnowflake tests generation and parsing of the Snowflake keys.
+func TestSnowflake(t *testing.T) {
+	clock := &fakeClock{now: SnowflakeEpoch.Add(time.Hour)}
+	ls, _ := New(Base62, 11)
+	sf, err := NewSnowflake(ls, SnowflakeConfig{Worker: 7, Clock: clock.Now})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for i := 0; i < 3; i++ {
+		key, err := sf.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	clock.Add(time.Millisecond)
+	key, _ := sf.Next()
+	keys = append(keys, key)
+
+	if !sort.StringsAreSorted(keys) {
+		t.Errorf("the keys aren't sorted: %v", keys)
+	}
+
+	expect := []SnowflakeID{
+		{SnowflakeEpoch.Add(time.Hour), 7, 0},
+		{SnowflakeEpoch.Add(time.Hour), 7, 1},
+		{SnowflakeEpoch.Add(time.Hour), 7, 2},
+		{SnowflakeEpoch.Add(time.Hour + time.Millisecond), 7, 0},
+	}
+
+	for i, key := range keys {
+		id, err := sf.Parse(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !id.Time.Equal(expect[i].Time) || id.Worker != expect[i].Worker ||
+			id.Sequence != expect[i].Sequence {
+			t.Errorf("%s: expected %v but %v", key, expect[i], id)
+		}
+	}
+}
+
+// TestSnowflakeClock tests the clock regression and the exhaustion
+// of the sequence.
+func TestSnowflakeClock(t *testing.T) {
+	clock := &fakeClock{now: SnowflakeEpoch.Add(time.Hour)}
+	ls, _ := New(Base62, 11)
+	sf, _ := NewSnowflake(ls, SnowflakeConfig{Clock: clock.Now})
+
+	last, _ := sf.NextID()
+	clock.Add(-time.Minute)
+	for i := 0; i < snowflakeMaxSequence*2; i++ {
+		id, err := sf.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id <= last {
+			t.Fatalf("the ID %d isn't greater than %d", id, last)
+		}
+
+		last = id
+	}
+
+	// Before the epoch.
+	clock.now = SnowflakeEpoch.Add(-time.Millisecond)
+	sf, _ = NewSnowflake(ls, SnowflakeConfig{Clock: clock.Now})
+	if _, err := sf.NextID(); err == nil {
+		t.Error("expected an error for the time before the epoch")
+	}
+}
+
+// TestSnowflakeConcurrent tests that the IDs are unique when they are
+// generated by several goroutines.
+func TestSnowflakeConcurrent(t *testing.T) {
+	ls, _ := New(Base62, 11)
+	sf, _ := NewSnowflake(ls, SnowflakeConfig{})
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		seen = make(map[uint64]bool)
+	)
+
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				id, err := sf.NextID()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("the ID %d is duplicated", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,71 @@
+package key
+
+import (
+	"errors"
+	"sort"
+)
+
+// Crockford32 is the Crockford's base32 alphabet used by ULID, it
+// doesn't contain the I, L, O and U letters. The alphabet is sorted
+// in the code-point order.
+const Crockford32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewSortable returns a new Locksmith object whose keys sort
+// lexicographically (as strings or bytes) in the same order as IDs.
+//
+// The characters of the alphabet are rearranged in the code-point
+// order, so the alphabet "cab" becomes "abc". The size of the key
+// must be fixed, i.e. greater than zero.
+//
+// Example usage:
+//
+//	ls, err := NewSortable("zyx0123", 4)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(ls.Alphabet()) // Output: "0123xyz"
+func NewSortable(alphabet string, size int) (*Locksmith, error) {
+	if size < 1 {
+		return &Locksmith{}, errors.New("sortable keys must have " +
+			"the fixed size")
+	}
+
+	chars := []rune(alphabet)
+	sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+
+	return New(string(chars), size)
+}
+
+// Sortable returns true if the keys sort lexicographically in the same
+// order as IDs, i.e. the key size is fixed, the characters of each
+// position are sorted in the code-point order and there is no tweak
+// character of the blocklist.
+func (ls *Locksmith) Sortable() bool {
+	return ls.size > 0 && ls.blocklist == nil && ls.ordered()
+}
+
+// The ordered returns true if the characters of all positions
+// are sorted in the code-point order.
+func (ls *Locksmith) ordered() bool {
+	sorted := func(chars []rune) bool {
+		for i := 1; i < len(chars); i++ {
+			if chars[i-1] >= chars[i] {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if ls.pattern == nil {
+		return sorted(ls.alphabet)
+	}
+
+	for _, p := range ls.pattern {
+		if !sorted(p.chars) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,72 @@
+package key
+
+import (
+	"sort"
+	"testing"
+)
+
+// TestNewSortable tests NewSortable function.
+func TestNewSortable(t *testing.T) {
+	if _, err := NewSortable("abc", 0); err == nil {
+		t.Error("expected an error for the dynamic size")
+	}
+
+	if _, err := NewSortable("abca", 3); err == nil {
+		t.Error("expected an error for the duplicates")
+	}
+
+	ls, err := NewSortable("zyx0123", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ls.Alphabet() != "0123xyz" {
+		t.Errorf("expected alphabet %q but %q", "0123xyz", ls.Alphabet())
+	}
+
+	if !ls.Sortable() {
+		t.Error("the Locksmith must be sortable")
+	}
+
+	// The keys sort in the same order as IDs.
+	keys := make([]string, 0, 500)
+	for id := uint64(0); id < 500; id++ {
+		key, _ := ls.Marshal(id * 4)
+		keys = append(keys, key)
+	}
+
+	if !sort.StringsAreSorted(keys) {
+		t.Error("the keys aren't sorted")
+	}
+}
+
+// TestSortable tests Sortable method.
+func TestSortable(t *testing.T) {
+	unsorted, _ := New("bac", 3)
+	dynamic, _ := New("abc")
+	sorted, _ := New("abc", 3)
+	grouped, _ := sorted.With(WithGroups(2, '-'))
+	blocked, _ := sorted.With(WithBlocklist("bad"))
+	pattern, _ := NewPattern("cvcvc", map[rune]string{'c': "bdf", 'v': "aeiou"})
+	proquint, _ := NewProquint(2)
+
+	tests := []struct {
+		name   string
+		ls     *Locksmith
+		expect bool
+	}{
+		{"unsorted", unsorted, false},
+		{"dynamic", dynamic, false},
+		{"sorted", sorted, true},
+		{"grouped", grouped, true},
+		{"blocked", blocked, false},
+		{"pattern", pattern, true},
+		{"proquint", proquint, true},
+	}
+
+	for _, test := range tests {
+		if r := test.ls.Sortable(); r != test.expect {
+			t.Errorf("%s: expected %v but %v", test.name, test.expect, r)
+		}
+	}
+}
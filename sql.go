@@ -0,0 +1,103 @@
+package key
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Value implements the driver.Valuer interface, the ID is stored
+// in the database as the integer.
+func (id ID[B]) Value() (driver.Value, error) {
+	if uint64(id) > math.MaxInt64 {
+		return nil, fmt.Errorf("%d is out of range of int64 column",
+			uint64(id))
+	}
+
+	return int64(id), nil
+}
+
+// Scan implements the sql.Scanner interface, it reads the ID
+// from the integer column.
+func (id *ID[B]) Scan(src any) error {
+	switch v := src.(type) {
+	case int64:
+		if v < 0 {
+			return fmt.Errorf("can't scan negative %d into ID", v)
+		}
+
+		*id = ID[B](v)
+	case []byte:
+		return id.Scan(string(v))
+	case string:
+		// Some drivers return the numeric columns as text.
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("can't scan %q into ID: %w", v, err)
+		}
+
+		*id = ID[B](n)
+	case nil:
+		return fmt.Errorf("can't scan NULL into ID")
+	default:
+		return fmt.Errorf("can't scan %T into ID", src)
+	}
+
+	return nil
+}
+
+// TextID is the ID that is stored in the database as the key string,
+// otherwise it's the same as the ID type.
+type TextID[B Binder] uint64
+
+// String returns the key of the ID.
+func (id TextID[B]) String() string {
+	return ID[B](id).String()
+}
+
+// MarshalText encodes the ID as the key.
+func (id TextID[B]) MarshalText() ([]byte, error) {
+	return ID[B](id).MarshalText()
+}
+
+// UnmarshalText decodes the key into the ID.
+func (id *TextID[B]) UnmarshalText(text []byte) error {
+	return (*ID[B])(id).UnmarshalText(text)
+}
+
+// MarshalJSON encodes the ID as the JSON string of the key.
+func (id TextID[B]) MarshalJSON() ([]byte, error) {
+	return ID[B](id).MarshalJSON()
+}
+
+// UnmarshalJSON decodes the JSON string of the key into the ID.
+func (id *TextID[B]) UnmarshalJSON(data []byte) error {
+	return (*ID[B])(id).UnmarshalJSON(data)
+}
+
+// Value implements the driver.Valuer interface, the ID is stored
+// in the database as the key string.
+func (id TextID[B]) Value() (driver.Value, error) {
+	text, err := id.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	return string(text), nil
+}
+
+// Scan implements the sql.Scanner interface, it reads the ID
+// from the text column with the key.
+func (id *TextID[B]) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		return id.UnmarshalText([]byte(v))
+	case []byte:
+		return id.UnmarshalText(v)
+	case nil:
+		return fmt.Errorf("can't scan NULL into ID")
+	default:
+		return fmt.Errorf("can't scan %T into ID", src)
+	}
+}
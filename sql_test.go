@@ -0,0 +1,210 @@
+package key
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDriver is the database driver with one table of one column,
+// the INSERT statement appends the argument to the table and the
+// SELECT statement returns all rows.
+type fakeDriver struct {
+	mu   sync.Mutex
+	rows []driver.Value
+}
+
+// Open returns a new connection.
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{d: d}, nil
+}
+
+// fakeConn is the connection of the fakeDriver.
+type fakeConn struct {
+	d *fakeDriver
+}
+
+// Prepare returns a new statement.
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{d: c.d, query: query}, nil
+}
+
+// Close closes the connection.
+func (c *fakeConn) Close() error { return nil }
+
+// Begin isn't supported.
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+// fakeStmt is the statement of the fakeDriver.
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+// Close closes the statement.
+func (s *fakeStmt) Close() error { return nil }
+
+// NumInput returns -1, the number of arguments isn't checked.
+func (s *fakeStmt) NumInput() int { return -1 }
+
+// Exec appends the argument to the table.
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	if strings.HasPrefix(s.query, "DELETE") {
+		s.d.rows = nil
+	} else {
+		s.d.rows = append(s.d.rows, args[0])
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+// Query returns all rows of the table.
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	rows := make([]driver.Value, len(s.d.rows))
+	copy(rows, s.d.rows)
+	return &fakeRows{rows: rows}, nil
+}
+
+// fakeRows is the result of the SELECT statement.
+type fakeRows struct {
+	rows []driver.Value
+}
+
+// Columns returns the names of columns.
+func (r *fakeRows) Columns() []string { return []string{"id"} }
+
+// Close closes the rows.
+func (r *fakeRows) Close() error { return nil }
+
+// Next returns the next row.
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	dest[0], r.rows = r.rows[0], r.rows[1:]
+	return nil
+}
+
+// The fake is the driver of the tests.
+var fake = &fakeDriver{}
+
+func init() {
+	sql.Register("keyfake", fake)
+}
+
+// The roundTrip inserts the value into the fake database
+// and scans it back into dest.
+func roundTrip(t *testing.T, value any, dest any) error {
+	t.Helper()
+
+	db, err := sql.Open("keyfake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("DELETE"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Exec("INSERT", value); err != nil {
+		return err
+	}
+
+	return db.QueryRow("SELECT").Scan(dest)
+}
+
+// TestIDSQL tests the Scanner and Valuer of the ID.
+func TestIDSQL(t *testing.T) {
+	var id ID[testOrder]
+	if err := roundTrip(t, ID[testOrder](10), &id); err != nil {
+		t.Fatal(err)
+	}
+
+	if id != 10 || id.String() != "1111111B" {
+		t.Errorf("Scan() = %d (%s), want 10", uint64(id), id)
+	}
+
+	if v, _ := ID[testOrder](10).Value(); v != int64(10) {
+		t.Errorf("Value() = %#v, want int64(10)", v)
+	}
+
+	// Some drivers return numeric columns as text.
+	if err := roundTrip(t, "58", &id); err != nil || id != 58 {
+		t.Errorf("Scan(\"58\") = %d, %v", uint64(id), err)
+	}
+
+	if err := roundTrip(t, []byte("59"), &id); err != nil || id != 59 {
+		t.Errorf("Scan([]byte(\"59\")) = %d, %v", uint64(id), err)
+	}
+
+	if err := roundTrip(t, ID[testOrder](math.MaxUint64), &id); err == nil {
+		t.Error("Value() of MaxUint64 should return an error")
+	}
+
+	if err := roundTrip(t, int64(-1), &id); err == nil {
+		t.Error("Scan(-1) should return an error")
+	}
+
+	if err := roundTrip(t, nil, &id); err == nil {
+		t.Error("Scan(nil) should return an error")
+	}
+
+	if err := roundTrip(t, 1.5, &id); err == nil {
+		t.Error("Scan(1.5) should return an error")
+	}
+}
+
+// TestTextIDSQL tests the Scanner and Valuer of the TextID.
+func TestTextIDSQL(t *testing.T) {
+	if v, _ := TextID[testOrder](10).Value(); v != "1111111B" {
+		t.Errorf("Value() = %#v, want \"1111111B\"", v)
+	}
+
+	var id TextID[testOrder]
+	if err := roundTrip(t, TextID[testOrder](10), &id); err != nil {
+		t.Fatal(err)
+	}
+
+	if id != 10 || id.String() != "1111111B" {
+		t.Errorf("Scan() = %d (%s), want 10", uint64(id), id)
+	}
+
+	if err := roundTrip(t, []byte("11111121"), &id); err != nil || id != 58 {
+		t.Errorf("Scan([]byte(\"11111121\")) = %d, %v", uint64(id), err)
+	}
+
+	if err := roundTrip(t, "1111111I", &id); err == nil {
+		t.Error("Scan() of invalid key should return an error")
+	}
+
+	if err := roundTrip(t, int64(10), &id); err == nil {
+		t.Error("Scan(10) should return an error")
+	}
+
+	if err := roundTrip(t, nil, &id); err == nil {
+		t.Error("Scan(nil) should return an error")
+	}
+
+	data, err := TextID[testOrder](10).MarshalJSON()
+	if err != nil || string(data) != `"1111111B"` {
+		t.Errorf("MarshalJSON() = %s, %v", data, err)
+	}
+
+	if err := id.UnmarshalJSON([]byte(`"11111121"`)); err != nil || id != 58 {
+		t.Errorf("UnmarshalJSON() = %d, %v", uint64(id), err)
+	}
+}
@@ -1,49 +1,157 @@
 package key
 
-// The reverse returns a slice of rune in reverse order.
-func reverse(v []rune) []rune {
-	for i, j := 0, len(v)-1; i < j; i, j = i+1, j-1 {
-		v[i], v[j] = v[j], v[i]
+import (
+	"math"
+	"math/bits"
+)
+
+// The digits converts a number into a sequence of digits in the
+// specified base, from the most significant digit to the least one.
+//
+// The result is padded with zeros on the left up to the size length,
+// if the size is zero, the result contains as few digits as possible
+// (but at least one digit).
+func digits(n, base uint64, size int) []int {
+	result := make([]int, 0, size)
+	for n >= base {
+		result = append(result, int(n%base))
+		n /= base
+	}
+	result = append(result, int(n))
+
+	for len(result) < size {
+		result = append(result, 0)
+	}
+
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
 	}
 
-	return v
+	return result
 }
 
-// The unlead removes the leading characters from the slice of rune.
-//
-// The leading character is the first character in the alphabet,
-// for example alphabet contains {'a', 'b', 'c', ..., 'z'} i.e.
-// first char is 'a' - it is lead char.
+// The number converts a sequence of digits in the specified base
+// back into a number. It returns false if the value overflows uint64.
+func number(digits []int, base uint64) (uint64, bool) {
+	var n uint64
+
+	for _, d := range digits {
+		hi, lo := bits.Mul64(n, base)
+		if hi != 0 {
+			return 0, false
+		}
+
+		sum, carry := bits.Add64(lo, uint64(d), 0)
+		if carry != 0 {
+			return 0, false
+		}
+
+		n = sum
+	}
+
+	return n, true
+}
+
+// The capacity returns the number of combinations of size digits in
+// the specified base. It returns false if the value overflows uint64.
+func capacity(base uint64, size int) (uint64, bool) {
+	result := uint64(1)
+	for i := 0; i < size; i++ {
+		hi, lo := bits.Mul64(result, base)
+		if hi != 0 {
+			return 0, false
+		}
+
+		result = lo
+	}
+
+	return result, true
+}
+
+// The luhn calculates the check digit for the sequence of digits in
+// the specified base using the Luhn mod N algorithm.
 //
-// So, if the rune slice is {'a', 'a', 'a', 'c', 'a', 'b'} -
-// function removes the first duplicates and returns a slice
-// without lead chars {'c', 'a', 'b'}.
-func unlead(lead rune, v []rune) []rune {
-	var seek int
+// The algorithm detects any single-digit error and most of the
+// transpositions of adjacent digits.
+func luhn(digits []int, base int) int {
+	factor, sum := 2, 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		addend := factor * digits[i]
+		sum += addend/base + addend%base
 
-	for seek < len(v)-1 && v[seek] == lead {
-		seek++
+		factor = 3 - factor // alternates between 2 and 1
 	}
 
-	return v[seek:]
+	return (base - sum%base) % base
 }
 
-// Pow calculates the exponentiation of a base to an exponent using
-// binary exponentiation. It returns the result of base raised to
-// the power of exponent.
-func pow(base, exponent int) int {
-	if exponent < 0 {
+// The mul returns the product of a and b, or 0 if it overflows uint64.
+func mul(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	if hi != 0 {
 		return 0
 	}
 
-	result := 1
-	for exponent > 0 {
-		if exponent&1 == 1 {
-			result *= base
-		}
-		base *= base
-		exponent >>= 1
+	return lo
+}
+
+// The digits128 converts the 128-bit number (hi, lo) into a sequence
+// of digits in the specified base, from the most significant digit to
+// the least one. The result is padded with zeros on the left up to
+// the size length.
+func digits128(hi, lo, base uint64, size int) []int {
+	result := make([]int, size)
+	for i := size - 1; i >= 0; i-- {
+		var r uint64
+		hi, r = hi/base, hi%base
+		lo, r = bits.Div64(r, lo, base)
+		result[i] = int(r)
 	}
 
 	return result
 }
+
+// The number128 converts a sequence of digits in the specified base
+// back into the 128-bit number (hi, lo). It returns false if the value
+// overflows 128 bits.
+func number128(digits []int, base uint64) (uint64, uint64, bool) {
+	var hi, lo uint64
+
+	for _, d := range digits {
+		h1, l1 := bits.Mul64(lo, base)
+		h2, l2 := bits.Mul64(hi, base)
+		if h2 != 0 {
+			return 0, 0, false
+		}
+
+		var carry uint64
+		hi, carry = bits.Add64(l2, h1, 0)
+		if carry != 0 {
+			return 0, 0, false
+		}
+
+		lo, carry = bits.Add64(l1, uint64(d), 0)
+		hi, carry = bits.Add64(hi, 0, carry)
+		if carry != 0 {
+			return 0, 0, false
+		}
+	}
+
+	return hi, lo, true
+}
+
+// The size128 returns the number of digits in the specified base
+// required to represent any 128-bit number.
+func size128(base uint64) int {
+	var size int
+
+	hi, lo := uint64(math.MaxUint64), uint64(math.MaxUint64)
+	for hi != 0 || lo != 0 {
+		var r uint64
+		hi, r = hi/base, hi%base
+		lo, _ = bits.Div64(r, lo, base)
+		size++
+	}
+
+	return size
+}
@@ -1,88 +1,85 @@
 package key
 
 import (
+	"math"
 	"reflect"
 	"testing"
 )
 
-// TestReverse tests the private reverse method.
-func TestReverse(t *testing.T) {
+// TestDigits tests the private digits and number functions.
+func TestDigits(t *testing.T) {
 	tests := []struct {
-		value  []rune
-		expect []rune
+		n      uint64
+		base   uint64
+		size   int
+		expect []int
 	}{
-		{[]rune{'c'}, []rune{'c'}},
-		{[]rune{'a', 'b', 'a'}, []rune{'a', 'b', 'a'}},
-		{[]rune{'a', 'b', 'c'}, []rune{'c', 'b', 'a'}},
+		{0, 10, 0, []int{0}},
+		{0, 10, 3, []int{0, 0, 0}},
+		{123, 10, 0, []int{1, 2, 3}},
+		{123, 10, 5, []int{0, 0, 1, 2, 3}},
+		{10, 3, 0, []int{1, 0, 1}},
+		{math.MaxUint64, 1 << 32, 0, []int{1<<32 - 1, 1<<32 - 1}},
 	}
 
 	for _, test := range tests {
-		// Reverse by link.
-		abc := make([]rune, len(test.value))
-		copy(abc, test.value)
-		reverse(abc)
-		if !reflect.DeepEqual(abc, test.expect) {
-			t.Errorf("abc: expected result %v but %v", test.expect, abc)
+		result := digits(test.n, test.base, test.size)
+		if !reflect.DeepEqual(result, test.expect) {
+			t.Errorf("expected result %v but %v", test.expect, result)
 		}
 
-		// Returned result.
-		def := make([]rune, len(test.value))
-		copy(def, test.value)
-		def = reverse(def)
-		if !reflect.DeepEqual(def, test.expect) {
-			t.Errorf("def: expected result %v but %v", test.expect, def)
+		n, ok := number(result, test.base)
+		if !ok || n != test.n {
+			t.Errorf("expected number %d but %d", test.n, n)
 		}
 	}
-}
 
-// TestUnlead tests the private unlead method.
-func TestUnlead(t *testing.T) {
-	tests := []struct {
-		value  []rune
-		expect []rune
-	}{
-		{[]rune{'b', 'c'}, []rune{'b', 'c'}},
-		{[]rune{'a', 'a', 'b', 'c'}, []rune{'b', 'c'}},
-		{[]rune{'a', 'a'}, []rune{'a'}},
+	// Overflow.
+	if _, ok := number([]int{1, 0, 0}, 1<<32); ok {
+		t.Error("expected an overflow")
 	}
 
-	for _, test := range tests {
-		abc := unlead('a', test.value)
-		if !reflect.DeepEqual(abc, test.expect) {
-			t.Errorf("expected result %v but %v\n", test.expect, abc)
-		}
+	if _, ok := number([]int{1<<32 - 1, 1<<32 - 1, 1}, 1<<32); ok {
+		t.Error("expected an overflow")
 	}
 }
 
-// TestPow tests pow functions.
-func TestPow(t *testing.T) {
-	// Test when the exponent is 0
-	result := pow(2, 0)
-	if result != 1 {
-		t.Errorf("Expected result to be 1, got %d", result)
+// TestCapacity tests the private capacity function.
+func TestCapacity(t *testing.T) {
+	if v, ok := capacity(3, 3); !ok || v != 27 {
+		t.Errorf("expected 27 but %d", v)
 	}
 
-	// Test when the exponent is positive
-	result = pow(2, 5)
-	if result != 32 {
-		t.Errorf("Expected result to be 32, got %d", result)
+	if v, ok := capacity(36, 12); !ok || v != 4738381338321616896 {
+		t.Errorf("expected 4738381338321616896 but %d", v)
 	}
 
-	// Test when the exponent is negative
-	result = pow(2, -3)
-	if result != 0 {
-		t.Errorf("Expected result to be 0, got %d", result)
+	if _, ok := capacity(36, 13); ok {
+		t.Error("expected an overflow")
 	}
+}
 
-	// Test when the base is negative
-	result = pow(-2, 4)
-	if result != 16 {
-		t.Errorf("Expected result to be 16, got %d", result)
+// TestLuhn tests the private luhn function.
+func TestLuhn(t *testing.T) {
+	// The classic Luhn algorithm for the decimal numbers.
+	value := []int{7, 9, 9, 2, 7, 3, 9, 8, 7, 1}
+	if d := luhn(value, 10); d != 3 {
+		t.Errorf("expected check digit 3 but %d", d)
 	}
 
-	// Test when the base is 0
-	result = pow(0, 5)
-	if result != 0 {
-		t.Errorf("Expected result to be 0, got %d", result)
+	// Any single-digit error must be detected.
+	check := luhn(value, 10)
+	for i := range value {
+		for d := 0; d < 10; d++ {
+			if d == value[i] {
+				continue
+			}
+
+			changed := append([]int{}, value...)
+			changed[i] = d
+			if luhn(changed, 10) == check {
+				t.Errorf("the error isn't detected: %v", changed)
+			}
+		}
 	}
 }
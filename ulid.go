@@ -0,0 +1,166 @@
+package key
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// The ulidMaxTime is the maximum 48-bit time of ULID in milliseconds.
+const ulidMaxTime = 1<<48 - 1
+
+// ULIDConfig is the configuration of the ULID generator.
+type ULIDConfig struct {
+	// Clock returns the current time, the nil value means time.Now.
+	Clock func() time.Time
+
+	// Entropy is the source of randomness, the nil value means
+	// the crypto/rand.Reader.
+	Entropy io.Reader
+}
+
+// ULIDParts is the decoded ULID.
+type ULIDParts struct {
+	Time    time.Time // time of generation with millisecond precision
+	Entropy [10]byte  // random part of the ULID
+}
+
+// ULID is a generator of the lexicographically sortable 128-bit IDs,
+// compatible with the ULID specification: 48 bits of the Unix time in
+// milliseconds followed by 80 random bits.
+//
+// Within the same millisecond the random part is incremented by one,
+// so the IDs of the generator are strictly increasing (monotonic).
+// If the clock moves backwards, the time of the last ID is used.
+//
+// The generator is safe for concurrent use by multiple goroutines.
+// It should be created by the NewULID function only.
+type ULID struct {
+	ls      *Locksmith
+	clock   func() time.Time
+	entropy io.Reader
+
+	mu      sync.Mutex
+	started bool   // true if at least one ID is generated
+	last    uint64 // time of the last ID in milliseconds
+	hi      uint16 // upper 16 bits of the random part of the last ID
+	lo      uint64 // lower 64 bits of the random part of the last ID
+}
+
+// NewULID returns a new ULID generator that renders IDs through the ls
+// Locksmith as the 128-bit values (see the MarshalUUID method).
+//
+// The alphabet of the Locksmith must be sorted in the code-point order.
+// If ls is nil the Crockford32 alphabet is used, so the keys are the
+// standard 26-character ULID strings.
+//
+// Example usage:
+//
+//	g, err := NewULID(nil, ULIDConfig{})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	key, _ := g.Next()
+//	fmt.Println(key) // Output like: "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+func NewULID(ls *Locksmith, cfg ULIDConfig) (*ULID, error) {
+	if ls == nil {
+		ls, _ = New(Crockford32)
+	}
+
+	if ls.pattern != nil || !ls.ordered() {
+		return &ULID{}, errors.New("the alphabet of the Locksmith " +
+			"must be sorted in the code-point order")
+	}
+
+	g := &ULID{
+		ls:      ls,
+		clock:   cfg.Clock,
+		entropy: cfg.Entropy,
+	}
+
+	if g.clock == nil {
+		g.clock = time.Now
+	}
+
+	if g.entropy == nil {
+		g.entropy = rand.Reader
+	}
+
+	return g, nil
+}
+
+// NextID returns the next ULID as 16 bytes.
+func (g *ULID) NextID() ([16]byte, error) {
+	var id [16]byte
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.clock().UnixMilli()
+	if now < 0 || now > ulidMaxTime {
+		return id, errors.New("the time is out of range of ULID")
+	}
+
+	if ms := uint64(now); ms > g.last || !g.started {
+		var random [10]byte
+		if _, err := io.ReadFull(g.entropy, random[:]); err != nil {
+			return id, err
+		}
+
+		g.started, g.last = true, ms
+		g.hi = binary.BigEndian.Uint16(random[:2])
+		g.lo = binary.BigEndian.Uint64(random[2:])
+	} else {
+		// The same millisecond or the clock regression,
+		// increment the 80-bit random part.
+		g.lo++
+		if g.lo == 0 {
+			if g.hi == 1<<16-1 {
+				return id, errors.New("the random part of ULID " +
+					"is exhausted within the millisecond")
+			}
+
+			g.hi++
+		}
+	}
+
+	binary.BigEndian.PutUint16(id[0:2], uint16(g.last>>32))
+	binary.BigEndian.PutUint32(id[2:6], uint32(g.last))
+	binary.BigEndian.PutUint16(id[6:8], g.hi)
+	binary.BigEndian.PutUint64(id[8:16], g.lo)
+
+	return id, nil
+}
+
+// Next returns the key of the next ULID.
+func (g *ULID) Next() (string, error) {
+	id, err := g.NextID()
+	if err != nil {
+		return "", err
+	}
+
+	return g.ls.MarshalUUID(id)
+}
+
+// Parse decodes the key generated by the ULID generator
+// and splits it into the time and random parts.
+func (g *ULID) Parse(key string) (ULIDParts, error) {
+	id, err := g.ls.UnmarshalUUID(key)
+	if err != nil {
+		return ULIDParts{}, err
+	}
+
+	parts := ULIDParts{
+		Time: time.UnixMilli(int64(
+			uint64(binary.BigEndian.Uint16(id[0:2]))<<32 |
+				uint64(binary.BigEndian.Uint32(id[2:6])),
+		)),
+	}
+	copy(parts.Entropy[:], id[6:])
+
+	return parts, nil
+}
@@ -0,0 +1,118 @@
+package key
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+	"time"
+)
+
+// TestNewULID tests NewULID function.
+func TestNewULID(t *testing.T) {
+	unsorted, _ := New("ba")
+	if _, err := NewULID(unsorted, ULIDConfig{}); err == nil {
+		t.Error("expected an error for the unsorted alphabet")
+	}
+
+	g, err := NewULID(nil, ULIDConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := g.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(key) != 26 {
+		t.Errorf("expected 26 chars but %d", len(key))
+	}
+}
+
+// TestULIDCompatible tests that the keys are the standard ULID strings.
+func TestULIDCompatible(t *testing.T) {
+	entropy := []byte{0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	g, err := NewULID(nil, ULIDConfig{
+		Clock:   func() time.Time { return time.UnixMilli(1469922850259) },
+		Entropy: bytes.NewReader(entropy),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := g.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Errorf("expected %q but %q", "01ARZ3NDEKTSV4RRFFQ69G5FAV", key)
+	}
+
+	parts, err := g.Parse(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parts.Time.UnixMilli() != 1469922850259 {
+		t.Errorf("unexpected time %v", parts.Time)
+	}
+
+	if !bytes.Equal(parts.Entropy[:], entropy) {
+		t.Errorf("expected %x but %x", entropy, parts.Entropy)
+	}
+}
+
+// TestULIDMonotonic tests that the keys are increasing within the same
+// millisecond and after the clock regression.
+func TestULIDMonotonic(t *testing.T) {
+	clock := &fakeClock{now: time.UnixMilli(1700000000000)}
+	ls, _ := New(Base62)
+	g, err := NewULID(ls, ULIDConfig{Clock: clock.Now})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for i := 0; i < 100; i++ {
+		if i == 50 {
+			clock.Add(-time.Second)
+		}
+
+		key, err := g.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	clock.Add(time.Minute)
+	key, _ := g.Next()
+	keys = append(keys, key)
+
+	for i := 1; i < len(keys); i++ {
+		if keys[i-1] >= keys[i] {
+			t.Fatalf("the keys aren't increasing: %s, %s", keys[i-1], keys[i])
+		}
+	}
+
+	if !sort.StringsAreSorted(keys) {
+		t.Error("the keys aren't sorted")
+	}
+
+	// Overflow of the random part.
+	overflow := bytes.Repeat([]byte{0xff}, 10)
+	g, _ = NewULID(nil, ULIDConfig{
+		Clock:   clock.Now,
+		Entropy: bytes.NewReader(overflow),
+	})
+
+	if _, err := g.Next(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.Next(); err == nil {
+		t.Error("expected an error for the exhausted random part")
+	}
+}
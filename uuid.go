@@ -0,0 +1,116 @@
+package key
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	// Base58 is the Bitcoin base58 alphabet, it doesn't contain the
+	// similar-looking characters 0, O, I and l.
+	Base58 = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+	// Base62 is the alphabet of digits, uppercase and lowercase
+	// latin letters.
+	Base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// UUIDSize returns the length of the keys generated by the MarshalUUID
+// method, i.e. the number of characters of the alphabet required to
+// represent any 128-bit value. For example it's 22 for the Base58 and
+// Base62 alphabets. The group separators and the check character of the
+// WithChecksum option aren't included.
+func (ls *Locksmith) UUIDSize() int {
+	return size128(uint64(len(ls.alphabet)))
+}
+
+// MarshalUUID converts a UUID (or any other 128-bit value) into a key.
+//
+// The key has the fixed length returned by the UUIDSize method, the
+// size of the Locksmith is ignored. The key is padded with the first
+// character of the alphabet, so all keys have the same length. The
+// groups of characters are applied, the blocklist isn't.
+//
+// The method doesn't support the patterned keys.
+//
+// Example usage:
+//
+//	ls, _ := New(Base62)
+//	id := uuid.MustParse("f47ac10b-58cc-4372-a567-0e02b2c3d479")
+//	key, err := ls.MarshalUUID(id)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(key) // Output: "7RKE2sawAICsEsyZKHWW6r"
+func (ls *Locksmith) MarshalUUID(uuid [16]byte) (string, error) {
+	if ls.pattern != nil {
+		return "", errors.New("UUID keys aren't supported " +
+			"for patterned keys")
+	}
+
+	hi := binary.BigEndian.Uint64(uuid[:8])
+	lo := binary.BigEndian.Uint64(uuid[8:])
+	value := digits128(hi, lo, uint64(len(ls.alphabet)), ls.UUIDSize())
+
+	return ls.format(value), nil
+}
+
+// UnmarshalUUID decodes a key created by the MarshalUUID method
+// and returns the UUID.
+//
+// Example usage:
+//
+//	ls, _ := New(Base62)
+//	id, err := ls.UnmarshalUUID("7RKE2sawAICsEsyZKHWW6r")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(uuid.UUID(id)) // Output: f47ac10b-58cc-4372-a567-0e02b2c3d479
+func (ls *Locksmith) UnmarshalUUID(key string) ([16]byte, error) {
+	var uuid [16]byte
+
+	if ls.pattern != nil {
+		return uuid, errors.New("UUID keys aren't supported " +
+			"for patterned keys")
+	}
+
+	key, err := ls.unprefix(key)
+	if err != nil {
+		return uuid, err
+	}
+
+	chars, err := ls.ungroup([]rune(key))
+	if err != nil {
+		return uuid, err
+	}
+
+	chars, err = ls.verify(chars)
+	if err != nil {
+		return uuid, err
+	}
+
+	if size := ls.UUIDSize(); len(chars) != size {
+		return uuid, errorf(ErrInvalidLength, "invalid key length, "+
+			"must be %d char(s) but %d char(s)", size, len(chars))
+	}
+
+	value := make([]int, len(chars))
+	for i, char := range chars {
+		index, ok := ls.indexOf[char]
+		if !ok {
+			return uuid, &InvalidCharError{Rune: char, Position: ls.offset(i)}
+		}
+
+		value[i] = index
+	}
+
+	hi, lo, ok := number128(value, uint64(len(ls.alphabet)))
+	if !ok {
+		return uuid, errorf(ErrOverflow, "the %q key is out of range", key)
+	}
+
+	binary.BigEndian.PutUint64(uuid[:8], hi)
+	binary.BigEndian.PutUint64(uuid[8:], lo)
+
+	return uuid, nil
+}
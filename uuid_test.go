@@ -0,0 +1,134 @@
+package key
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// parseUUID converts the canonical text form of UUID into bytes.
+func parseUUID(t *testing.T, s string) [16]byte {
+	var uuid [16]byte
+
+	b, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil || len(b) != 16 {
+		t.Fatalf("invalid UUID %s", s)
+	}
+
+	copy(uuid[:], b)
+	return uuid
+}
+
+// TestUUIDSize tests UUIDSize method.
+func TestUUIDSize(t *testing.T) {
+	tests := []struct {
+		alphabet string
+		expect   int
+	}{
+		{"01", 128},
+		{"0123456789abcdef", 32},
+		{Base58, 22},
+		{Base62, 22},
+		{"0123456789ABCDEFGHJKMNPQRSTVWXYZ", 26},
+	}
+
+	for _, test := range tests {
+		ls, _ := New(test.alphabet)
+		if size := ls.UUIDSize(); size != test.expect {
+			t.Errorf("%s: expected %d but %d", test.alphabet, test.expect, size)
+		}
+	}
+}
+
+// TestMarshalUUID tests MarshalUUID and UnmarshalUUID methods.
+func TestMarshalUUID(t *testing.T) {
+	tests := []struct {
+		uuid   string
+		base62 string
+		base58 string
+	}{
+		{
+			"00000000-0000-0000-0000-000000000000",
+			"0000000000000000000000",
+			"1111111111111111111111",
+		},
+		{
+			"ffffffff-ffff-ffff-ffff-ffffffffffff",
+			"7n42DGM5Tflk9n8mt7Fhc7",
+			"YcVfxkQb6JRzqk5kF2tNLv",
+		},
+		{
+			"f47ac10b-58cc-4372-a567-0e02b2c3d479",
+			"7RKE2sawAICsEsyZKHWW6r",
+			"XBz3jkFgmHZpHEmghHCsXn",
+		},
+		{
+			"01890a5d-ac96-774b-bcce-b302099a8057",
+			"02tcRIyrxLXTR81B3dqdOx",
+			"1BzmjTFLHWXwiSK4y3H5iW",
+		},
+	}
+
+	base62, _ := New(Base62, 8) // the size is ignored
+	base58, _ := New(Base58)
+	for _, test := range tests {
+		uuid := parseUUID(t, test.uuid)
+		for _, c := range []struct {
+			ls     *Locksmith
+			expect string
+		}{{base62, test.base62}, {base58, test.base58}} {
+			key, err := c.ls.MarshalUUID(uuid)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if key != c.expect {
+				t.Errorf("%s: expected %q but %q", test.uuid, c.expect, key)
+			}
+
+			result, err := c.ls.UnmarshalUUID(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result != uuid {
+				t.Errorf("%s: expected %x but %x", key, uuid, result)
+			}
+		}
+	}
+}
+
+// TestUnmarshalUUIDErrors tests UnmarshalUUID method with invalid keys.
+func TestUnmarshalUUIDErrors(t *testing.T) {
+	ls, _ := New(Base62)
+	tests := []string{
+		"7n42DGM5Tflk9n8mt7Fhc",   // short
+		"7n42DGM5Tflk9n8mt7Fhc77", // long
+		"7n42DGM5Tflk9n8mt7Fhc8",  // overflow
+		"7n42DGM5Tflk9n8mt7Fhc-",  // wrong char
+	}
+
+	for _, test := range tests {
+		if _, err := ls.UnmarshalUUID(test); err == nil {
+			t.Errorf("%s: expected an error", test)
+		}
+	}
+
+	// Groups of characters.
+	ls, _ = ls.With(WithGroups(11, '-'))
+	uuid := parseUUID(t, "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	key, _ := ls.MarshalUUID(uuid)
+	if key != "7RKE2sawAIC-sEsyZKHWW6r" {
+		t.Errorf("unexpected key %q", key)
+	}
+
+	if result, err := ls.UnmarshalUUID(key); err != nil || result != uuid {
+		t.Errorf("expected %x but %x (%v)", uuid, result, err)
+	}
+
+	// Patterned keys.
+	ls, _ = NewPattern("cvcvc", nil)
+	if _, err := ls.MarshalUUID(uuid); err == nil {
+		t.Error("expected an error for the patterned keys")
+	}
+}
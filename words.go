@@ -0,0 +1,483 @@
+package key
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	//go:embed wordlists/bip39_english.txt
+	bip39English string
+
+	//go:embed wordlists/eff_large.txt
+	effLarge string
+)
+
+// BIP39 returns a copy of the BIP-0039 English wordlist.
+//
+// The list contains 2048 words, each word is uniquely identified
+// by its first four letters, so it can be used together with the
+// WordAbbrev(4) option.
+func BIP39() []string {
+	return strings.Fields(bip39English)
+}
+
+// EFFLarge returns a copy of the EFF large wordlist for passphrases.
+//
+// The list contains 7776 words (i.e. five dice rolls per word),
+// the words aren't unique by a short prefix.
+func EFFLarge() []string {
+	return strings.Fields(effLarge)
+}
+
+// WordOption is a function that configures optional behavior
+// of the Wordsmith object.
+type WordOption func(*Wordsmith) error
+
+// WordSeparator sets the string that separates the words in the key.
+// The default separator is "-".
+func WordSeparator(sep string) WordOption {
+	return func(ws *Wordsmith) error {
+		if sep == "" {
+			return errors.New("blank word separator")
+		}
+
+		ws.separator = sep
+		return nil
+	}
+}
+
+// WordAbbrev allows to decode words by their first n letters,
+// for example, with the BIP39 wordlist and n as 4 the "orbi" and
+// "orbit" are both decoded as the "orbit" word.
+//
+// The wordlist must be unique by the first n letters.
+func WordAbbrev(n int) WordOption {
+	return func(ws *Wordsmith) error {
+		if n < 1 {
+			return fmt.Errorf("incorrect abbreviation length %d", n)
+		}
+
+		ws.abbrev = n
+		return nil
+	}
+}
+
+// WordChecksum appends one check word to the generated keys.
+//
+// The check word is calculated using the Luhn mod N algorithm, where
+// N is the size of the wordlist, it catches any single mistyped word
+// and most of the swaps of the adjacent words.
+func WordChecksum() WordOption {
+	return func(ws *Wordsmith) error {
+		ws.checksum = true
+		return nil
+	}
+}
+
+// NewWordsmith returns a new Wordsmith object. It takes in the
+// wordlist, the size of the key in words and optional settings.
+//
+//   - words is a list of unique words used as digits of the key. It must
+//     contain at least 2 words. A word can contain the separator (like
+//     "t-shirt"), if any key is split into the words unambiguously. The
+//     BIP39 and EFFLarge functions return the standard wordlists.
+//
+//   - size is the fixed number of words in the key (excluding the check
+//     word). If size is set to zero, the number of words will be dynamic.
+//
+// Example usage:
+//
+//	ws, err := NewWordsmith(BIP39(), 3, WordAbbrev(4))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	key, err := ws.Marshal(10)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(key) // Output: "abandon-abandon-access"
+func NewWordsmith(words []string, size int, opts ...WordOption) (*Wordsmith, error) {
+	if len(words) < 2 {
+		return &Wordsmith{}, errors.New("the wordlist must contain " +
+			"at least 2 words")
+	}
+
+	if size < 0 {
+		return &Wordsmith{}, ErrNegativeSize
+	}
+
+	wordsmith := &Wordsmith{
+		size:      uint64(size),
+		total:     uint64(math.MaxUint64),
+		words:     make([]string, len(words)),
+		indexOf:   make(map[string]int, len(words)),
+		separator: "-",
+	}
+	copy(wordsmith.words, words)
+
+	for _, opt := range opts {
+		if err := opt(wordsmith); err != nil {
+			return &Wordsmith{}, err
+		}
+	}
+
+	for i, word := range wordsmith.words {
+		if word == "" {
+			return &Wordsmith{}, errors.New("the wordlist contains " +
+				"a blank word")
+		}
+
+		if _, ok := wordsmith.indexOf[word]; ok {
+			return &Wordsmith{}, errorf(ErrDuplicateChar,
+				"the %q word is repeated in the wordlist",
+				word,
+			)
+		}
+
+		wordsmith.indexOf[word] = i
+	}
+
+	// A word can contain the separator (like "t-shirt" in the EFF list),
+	// but the key must be split into the words unambiguously.
+	for _, word := range wordsmith.words {
+		if n := strings.Count(word, wordsmith.separator) + 1; n > wordsmith.span {
+			wordsmith.span = n
+		}
+	}
+
+	if word, ok := wordsmith.ambiguous(); ok {
+		return &Wordsmith{}, fmt.Errorf("the %q word is ambiguous "+
+			"with the %q separator", word, wordsmith.separator)
+	}
+
+	if wordsmith.abbrev > 0 {
+		wordsmith.abbrevOf = make(map[string]int, len(words))
+		for i, word := range wordsmith.words {
+			if utf8.RuneCountInString(word) < wordsmith.abbrev {
+				continue // such word can be specified in full form only
+			}
+
+			head := string([]rune(word)[:wordsmith.abbrev])
+			if _, ok := wordsmith.abbrevOf[head]; ok {
+				return &Wordsmith{}, fmt.Errorf("the wordlist isn't "+
+					"unique by the first %d letters: %q",
+					wordsmith.abbrev, head)
+			}
+
+			wordsmith.abbrevOf[head] = i
+		}
+	}
+
+	if wordsmith.size != 0 {
+		base := uint64(len(wordsmith.words))
+		if total, ok := capacity(base, size); ok {
+			wordsmith.total = total
+		}
+	}
+
+	return wordsmith, nil
+}
+
+// Wordsmith is a key generation object that uses words instead of
+// characters, it produces the speakable keys like "orbit-cannon-matrix".
+// It can be created correctly through the NewWordsmith function only.
+type Wordsmith struct {
+	size      uint64         // number of words in the key
+	total     uint64         // maximum allowable key value
+	words     []string       // list of words to generate the key
+	indexOf   map[string]int // the map of matching words of the list
+	abbrevOf  map[string]int // the map of abbreviations of the words
+	abbrev    int            // length of abbreviation, 0 if disabled
+	separator string         // separator between words
+	span      int            // maximum number of separator-split parts of a word
+	checksum  bool           // true if the check word is appended
+}
+
+// Words returns a copy of the current wordlist.
+func (ws *Wordsmith) Words() []string {
+	result := make([]string, len(ws.words))
+	copy(result, ws.words)
+	return result
+}
+
+// Size returns the number of words in the key without the check word.
+func (ws *Wordsmith) Size() uint64 {
+	return ws.size
+}
+
+// Total returns the highest possible iteration number.
+//
+// If the number of keys doesn't fit into uint64 (including the dynamic
+// size of the key) the method returns MaxUint64, in this case any
+// uint64 value can be used as ID.
+func (ws *Wordsmith) Total() uint64 {
+	return ws.total
+}
+
+// Marshal converts an ID into a key of words.
+//
+// The ID should be less than the total number of possible keys,
+// otherwise, an error will be returned. For the fixed size keys
+// the result is padded with the first word of the list.
+func (ws *Wordsmith) Marshal(id uint64) (string, error) {
+	if id >= ws.total && ws.total != math.MaxUint64 {
+		return "", errorf(ErrIDOutOfRange,
+			"%d is large ID for key generation", id)
+	}
+
+	base := len(ws.words)
+	value := digits(id, uint64(base), int(ws.size))
+	if ws.checksum {
+		value = append(value, luhn(value, base))
+	}
+
+	result := make([]string, len(value))
+	for i, d := range value {
+		result[i] = ws.words[d]
+	}
+
+	return strings.Join(result, ws.separator), nil
+}
+
+// Unmarshal decodes a key of words and returns its corresponding ID.
+//
+// If the WordAbbrev option is set, the words can be specified by
+// their first letters. If the WordChecksum option is set, the last
+// word must be a valid check word.
+func (ws *Wordsmith) Unmarshal(key string) (uint64, error) {
+	value, err := ws.parse(key)
+	if err != nil {
+		return 0, err
+	}
+
+	base := len(ws.words)
+	if ws.checksum {
+		if len(value) < 2 {
+			return 0, errorf(ErrInvalidLength, "the key is too short "+
+				"to contain a check word")
+		}
+
+		last := len(value) - 1
+		if luhn(value[:last], base) != value[last] {
+			return 0, errorf(ErrInvalidChar, "invalid check word")
+		}
+
+		value = value[:last]
+	}
+
+	if l := uint64(len(value)); ws.size > 0 && l != ws.size {
+		return 0, errorf(ErrInvalidLength, "invalid key length, "+
+			"must be %d word(s) but %d word(s)", ws.size, l)
+	}
+
+	id, ok := number(value, uint64(base))
+	if !ok || (id >= ws.total && ws.total != math.MaxUint64) {
+		return 0, errorf(ErrOverflow, "the %q key is out of range", key)
+	}
+
+	return id, nil
+}
+
+// The parse splits the key into words and returns their indexes.
+//
+// The words that contain the separator are split into parts too, so the
+// key is parsed as the sequence of parts. The full words are tried first,
+// then the abbreviations.
+func (ws *Wordsmith) parse(key string) ([]int, error) {
+	parts := strings.Split(key, ws.separator)
+	if result, ok := ws.split(parts, false); ok {
+		return result, nil
+	}
+
+	if ws.abbrev > 0 {
+		if result, ok := ws.split(parts, true); ok {
+			return result, nil
+		}
+	}
+
+	// Find the first part that doesn't start any word.
+	result := make([]int, 0, len(parts))
+	for i := 0; i < len(parts); {
+		n, index := 0, 0
+		for l := ws.span; l > 0 && n == 0; l-- {
+			if i+l > len(parts) {
+				continue
+			}
+
+			if idx, ok := ws.word(parts[i:i+l], ws.abbrev > 0); ok {
+				n, index = l, idx
+			}
+		}
+
+		if n == 0 {
+			return nil, &InvalidWordError{
+				Word:     parts[i],
+				Position: len(result),
+			}
+		}
+
+		result = append(result, index)
+		i += n
+	}
+
+	return result, nil
+}
+
+// The split returns the indexes of the words that make up the parts,
+// it returns false if the parts can't be split into the words.
+func (ws *Wordsmith) split(parts []string, abbrev bool) ([]int, bool) {
+	// The size[i] is the number of parts of the word that starts
+	// at the i-th part if the rest of the parts can be split, or 0.
+	size := make([]int, len(parts)+1)
+	index := make([]int, len(parts)+1)
+	for i := len(parts) - 1; i >= 0; i-- {
+		for l := 1; l <= ws.span && i+l <= len(parts); l++ {
+			if i+l < len(parts) && size[i+l] == 0 {
+				continue
+			}
+
+			if idx, ok := ws.word(parts[i:i+l], abbrev); ok {
+				size[i], index[i] = l, idx
+				break
+			}
+		}
+	}
+
+	if size[0] == 0 {
+		return nil, false
+	}
+
+	result := make([]int, 0, len(parts))
+	for i := 0; i < len(parts); i += size[i] {
+		result = append(result, index[i])
+	}
+
+	return result, true
+}
+
+// The word returns the index of the word of the parts, the single part
+// can be the abbreviation if abbrev is true.
+func (ws *Wordsmith) word(parts []string, abbrev bool) (int, bool) {
+	if len(parts) == 1 && abbrev {
+		return ws.lookup(parts[0])
+	}
+
+	index, ok := ws.indexOf[strings.Join(parts, ws.separator)]
+	return index, ok
+}
+
+// The ambiguous returns the word if some sequence of parts can be split
+// into the words in two ways, i.e. the words aren't uniquely decodable.
+//
+// It's the Sardinas-Patterson algorithm over the parts of the words:
+// it follows the dangling suffixes, i.e. the rest of one word (or suffix)
+// after the other one, the words are ambiguous if some suffix is a word.
+func (ws *Wordsmith) ambiguous() (string, bool) {
+	if ws.span < 2 {
+		return "", false // the words don't contain the separator
+	}
+
+	type suffix struct {
+		parts []string
+		word  string // the word that the suffix starts from
+	}
+
+	// The words that contain the separator by their first part.
+	long := make(map[string][][]string)
+	for _, word := range ws.words {
+		if parts := strings.Split(word, ws.separator); len(parts) > 1 {
+			long[parts[0]] = append(long[parts[0]], parts)
+		}
+	}
+
+	var queue []suffix
+	seen := make(map[string]bool)
+	push := func(parts []string, word string) bool {
+		rest := strings.Join(parts, ws.separator)
+		if _, ok := ws.indexOf[rest]; ok {
+			return true
+		}
+
+		if !seen[rest] {
+			seen[rest] = true
+			queue = append(queue, suffix{parts: parts, word: word})
+		}
+
+		return false
+	}
+
+	// The words that start with other words.
+	for _, list := range long {
+		for _, parts := range list {
+			for k := 1; k < len(parts); k++ {
+				head := strings.Join(parts[:k], ws.separator)
+				if _, ok := ws.indexOf[head]; !ok {
+					continue
+				}
+
+				word := strings.Join(parts, ws.separator)
+				if push(parts[k:], word) {
+					return word, true
+				}
+			}
+		}
+	}
+
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+
+		// The words that the suffix starts with.
+		for k := 1; k < len(s.parts); k++ {
+			head := strings.Join(s.parts[:k], ws.separator)
+			if _, ok := ws.indexOf[head]; ok && push(s.parts[k:], s.word) {
+				return s.word, true
+			}
+		}
+
+		// The words that start with the suffix.
+		for _, parts := range long[s.parts[0]] {
+			if len(parts) > len(s.parts) && hasPrefix(parts, s.parts) &&
+				push(parts[len(s.parts):], s.word) {
+				return s.word, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// The hasPrefix returns true if the parts start with the prefix parts.
+func hasPrefix(parts, prefix []string) bool {
+	for i, part := range prefix {
+		if parts[i] != part {
+			return false
+		}
+	}
+
+	return true
+}
+
+// The lookup returns the index of the word or its abbreviation,
+// it returns false if the token isn't found.
+func (ws *Wordsmith) lookup(token string) (int, bool) {
+	if index, ok := ws.indexOf[token]; ok {
+		return index, true
+	}
+
+	if ws.abbrev > 0 && utf8.RuneCountInString(token) >= ws.abbrev {
+		head := string([]rune(token)[:ws.abbrev])
+		if index, ok := ws.abbrevOf[head]; ok &&
+			strings.HasPrefix(ws.words[index], token) {
+			return index, true
+		}
+	}
+
+	return 0, false
+}
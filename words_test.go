@@ -0,0 +1,250 @@
+package key
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestWordlists tests the embedded wordlists.
+func TestWordlists(t *testing.T) {
+	if l := len(BIP39()); l != 2048 {
+		t.Errorf("expected 2048 words in BIP39 but %d", l)
+	}
+
+	if l := len(EFFLarge()); l != 7776 {
+		t.Errorf("expected 7776 words in EFFLarge but %d", l)
+	}
+
+	// The functions return copies of the lists.
+	words := BIP39()
+	words[0] = "changed"
+	if BIP39()[0] != "abandon" {
+		t.Error("the wordlist must not be changed outside")
+	}
+}
+
+// TestNewWordsmith tests NewWordsmith function.
+func TestNewWordsmith(t *testing.T) {
+	tests := []struct {
+		name  string
+		words []string
+		size  int
+		opts  []WordOption
+	}{
+		{"one word", []string{"one"}, 3, nil},
+		{"duplicates", []string{"one", "two", "one"}, 3, nil},
+		{"blank word", []string{"one", "", "two"}, 3, nil},
+		{"negative size", []string{"one", "two"}, -1, nil},
+		{"blank separator", []string{"one", "two"}, 3,
+			[]WordOption{WordSeparator("")}},
+		{"ambiguous word", []string{"one", "two", "one-two"}, 3, nil},
+		{"overlapped words", []string{"a", "a-b", "b-c", "c"}, 2, nil},
+		{"not unique abbreviation", EFFLarge(), 3,
+			[]WordOption{WordAbbrev(4)}},
+	}
+
+	for _, test := range tests {
+		if _, err := NewWordsmith(test.words, test.size, test.opts...); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+
+	ws, err := NewWordsmith(BIP39(), 3, WordAbbrev(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ws.Total() != 2048*2048*2048 {
+		t.Errorf("expected total to be %d, got %d", 2048*2048*2048, ws.Total())
+	}
+
+	if ws.Size() != 3 {
+		t.Errorf("expected size to be 3, got %d", ws.Size())
+	}
+}
+
+// TestWordsmithMarshal tests Marshal and Unmarshal methods of Wordsmith.
+func TestWordsmithMarshal(t *testing.T) {
+	tests := []struct {
+		size   int
+		opts   []WordOption
+		id     uint64
+		expect string
+	}{
+		{3, nil, 0, "abandon-abandon-abandon"},
+		{3, nil, 10, "abandon-abandon-access"},
+		{2, nil, 2048, "ability-abandon"},
+		{0, nil, 10, "access"},
+		{0, nil, 2049, "ability-ability"},
+		{2, []WordOption{WordSeparator(" ")}, 10, "abandon access"},
+	}
+
+	for _, test := range tests {
+		ws, err := NewWordsmith(BIP39(), test.size, test.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		key, err := ws.Marshal(test.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if key != test.expect {
+			t.Errorf("expected %q but %q", test.expect, key)
+		}
+
+		id, err := ws.Unmarshal(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id != test.id {
+			t.Errorf("expected %d but %d", test.id, id)
+		}
+	}
+
+	ws, _ := NewWordsmith(BIP39(), 2)
+	if _, err := ws.Marshal(ws.Total()); err == nil {
+		t.Error("expected an error for the large ID")
+	}
+
+	if _, err := ws.Unmarshal("abandon"); err == nil {
+		t.Error("expected an error for the wrong number of words")
+	}
+
+	if _, err := ws.Unmarshal("abandon-orbitt"); err == nil {
+		t.Error("expected an error for the unknown word")
+	}
+}
+
+// TestWordsmithAbbrev tests decoding of the abbreviated words.
+func TestWordsmithAbbrev(t *testing.T) {
+	ws, err := NewWordsmith(BIP39(), 3, WordAbbrev(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	full, err := ws.Unmarshal("orbit-cannon-matrix")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"orbi-cann-matr", "orbit-canno-matri"} {
+		id, err := ws.Unmarshal(key)
+		if err != nil {
+			t.Errorf("%s: %v", key, err)
+		}
+
+		if id != full {
+			t.Errorf("%s: expected %d but %d", key, full, id)
+		}
+	}
+
+	// Too short or not matching the rest of the word.
+	for _, key := range []string{"orb-cann-matr", "orbx-cann-matr",
+		"orbiter-cann-matr"} {
+		if _, err := ws.Unmarshal(key); err == nil {
+			t.Errorf("%s: expected an error", key)
+		}
+	}
+}
+
+// TestWordsmithChecksum tests keys with the check word.
+func TestWordsmithChecksum(t *testing.T) {
+	ws, err := NewWordsmith(BIP39(), 3, WordChecksum())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ws.Marshal(123456789)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if words := strings.Split(key, "-"); len(words) != 4 {
+		t.Fatalf("expected 4 words but %d", len(words))
+	}
+
+	id, err := ws.Unmarshal(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id != 123456789 {
+		t.Errorf("expected %d but %d", 123456789, id)
+	}
+
+	// Swap two adjacent words.
+	words := strings.Split(key, "-")
+	words[0], words[1] = words[1], words[0]
+	if _, err := ws.Unmarshal(strings.Join(words, "-")); err == nil {
+		t.Error("expected an error for the swapped words")
+	}
+
+	// Replace a word.
+	words = strings.Split(key, "-")
+	words[2] = "zoo"
+	if _, err := ws.Unmarshal(strings.Join(words, "-")); err == nil {
+		t.Error("expected an error for the replaced word")
+	}
+}
+
+// TestWordsmithSeparatorInWord tests words that contain the separator.
+func TestWordsmithSeparatorInWord(t *testing.T) {
+	ws, err := NewWordsmith(EFFLarge(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tShirt := ws.indexOf["t-shirt"]
+	id := uint64(tShirt)*7776 + uint64(tShirt)
+	key, _ := ws.Marshal(id)
+	if key != "abacus-t-shirt-t-shirt" {
+		t.Errorf("unexpected key %q", key)
+	}
+
+	result, err := ws.Unmarshal(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != id {
+		t.Errorf("expected %d but %d", id, result)
+	}
+}
+
+// TestWordsmithSeparatorRoundTrip tests that all keys of the wordlists
+// with the separator in words are unique and decoded into their IDs.
+func TestWordsmithSeparatorRoundTrip(t *testing.T) {
+	lists := [][]string{
+		{"cat", "dog", "jack-in-the-box"},
+		{"cat", "shirt", "t-shirt", "yo-yo", "x-cat-y"},
+		{"x", "x-y", "y-y-z"},
+	}
+
+	for _, words := range lists {
+		ws, err := NewWordsmith(words, 3, WordChecksum())
+		if err != nil {
+			t.Fatalf("%v: %v", words, err)
+		}
+
+		seen := make(map[string]uint64, ws.Total())
+		for id := uint64(0); id < ws.Total(); id++ {
+			key, _ := ws.Marshal(id)
+			if other, ok := seen[key]; ok {
+				t.Fatalf("IDs %d and %d have the same key %q", other, id, key)
+			}
+			seen[key] = id
+
+			if result, err := ws.Unmarshal(key); err != nil || result != id {
+				t.Errorf("expected %d for %q but %d (%v)", id, key, result, err)
+			}
+		}
+	}
+
+	ws, _ := NewWordsmith(lists[0], 2)
+	if id, err := ws.Unmarshal("cat-jack-in-the-box"); err != nil || id != 2 {
+		t.Errorf("expected 2 but %d (%v)", id, err)
+	}
+}
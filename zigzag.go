@@ -0,0 +1,61 @@
+package key
+
+// MarshalInt64 converts a signed ID into a key.
+//
+// The ID is mapped to unsigned value using the zigzag encoding:
+// 0, -1, 1, -2, 2, ... become 0, 1, 2, 3, 4, ..., so the numbers close
+// to zero (both positive and negative) give short keys for the dynamic
+// size. The math.MinInt64 is mapped to MaxUint64.
+//
+// For the fixed size keys the mapped value must be less than Total,
+// i.e. the ID must be in the range -Total/2 <= ID <= (Total-1)/2
+// (with the integer division), for example from -13 to 13 for
+// the 27 keys.
+//
+// Example usage:
+//
+//	ls, _ := New("abc")
+//	key, err := ls.MarshalInt64(-5)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(key) // Output: "baa"
+func (ls *Locksmith) MarshalInt64(id int64) (string, error) {
+	if !ls.fits(zigzag(id)) {
+		return "", errorf(ErrIDOutOfRange,
+			"%d is out of range for key generation", id)
+	}
+
+	return ls.Marshal(zigzag(id))
+}
+
+// UnmarshalInt64 decodes a key and returns its corresponding signed ID.
+// It's the inverse of the MarshalInt64 method.
+//
+// Example usage:
+//
+//	ls, _ := New("abc")
+//	id, err := ls.UnmarshalInt64("baa")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(id) // Output: -5
+func (ls *Locksmith) UnmarshalInt64(key string) (int64, error) {
+	id, err := ls.Unmarshal(key)
+	if err != nil {
+		return 0, err
+	}
+
+	return unzigzag(id), nil
+}
+
+// The zigzag maps the signed integer to unsigned one so that the
+// numbers with a small absolute value have a small value too.
+func zigzag(n int64) uint64 {
+	return uint64(n<<1) ^ uint64(n>>63)
+}
+
+// The unzigzag is the inverse of the zigzag function.
+func unzigzag(n uint64) int64 {
+	return int64(n>>1) ^ -int64(n&1)
+}
@@ -0,0 +1,101 @@
+package key
+
+import (
+	"math"
+	"testing"
+)
+
+// TestZigzag tests the private zigzag and unzigzag functions.
+func TestZigzag(t *testing.T) {
+	tests := []struct {
+		value  int64
+		expect uint64
+	}{
+		{0, 0},
+		{-1, 1},
+		{1, 2},
+		{-2, 3},
+		{2, 4},
+		{math.MaxInt64, math.MaxUint64 - 1},
+		{math.MinInt64, math.MaxUint64},
+	}
+
+	for _, test := range tests {
+		if r := zigzag(test.value); r != test.expect {
+			t.Errorf("%d: expected %d but %d", test.value, test.expect, r)
+		}
+
+		if r := unzigzag(test.expect); r != test.value {
+			t.Errorf("%d: expected %d but %d", test.expect, test.value, r)
+		}
+	}
+}
+
+// TestMarshalInt64 tests MarshalInt64 and UnmarshalInt64 methods.
+func TestMarshalInt64(t *testing.T) {
+	ls, err := New("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		id  int64
+		key string
+	}{
+		{0, "a"},
+		{-1, "b"},
+		{1, "c"},
+		{-5, "baa"},
+		{math.MinInt64, "11112220022122120101211020120210210211220"},
+	}
+
+	for _, test := range tests {
+		key, err := ls.MarshalInt64(test.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The expected keys are written in the ternary digits.
+		expect := []rune(test.key)
+		for i, char := range expect {
+			if char >= '0' && char <= '2' {
+				expect[i] = 'a' + char - '0'
+			}
+		}
+
+		if key != string(expect) {
+			t.Errorf("%d: expected %q but %q", test.id, string(expect), key)
+		}
+
+		id, err := ls.UnmarshalInt64(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id != test.id {
+			t.Errorf("expected %d but %d", test.id, id)
+		}
+	}
+}
+
+// TestMarshalInt64Range tests MarshalInt64 method with the fixed size.
+func TestMarshalInt64Range(t *testing.T) {
+	ls, _ := New("abc", 3) // 27 keys, from -13 to 13
+
+	for id := int64(-13); id <= 13; id++ {
+		key, err := ls.MarshalInt64(id)
+		if err != nil {
+			t.Fatalf("%d: %v", id, err)
+		}
+
+		if r, _ := ls.UnmarshalInt64(key); r != id {
+			t.Errorf("expected %d but %d", id, r)
+		}
+	}
+
+	for _, id := range []int64{-14, 14, math.MinInt64, math.MaxInt64} {
+		if _, err := ls.MarshalInt64(id); err == nil {
+			t.Errorf("%d: expected an error", id)
+		}
+	}
+}